@@ -12,6 +12,9 @@ type VectorBuilder struct {
 }
 
 func (v *VectorBuilder) Add(ctx context.Context, w *World, value Node) error {
+	if err := w.useConses(ctx, 1); err != nil {
+		return err
+	}
 	v.list = append(v.list, value)
 	return nil
 }
@@ -158,6 +161,9 @@ func funCreateArray(ctx context.Context, w *World, args []Node) (Node, error) {
 	if size >= 1234567890 {
 		return callHandler[Node](ctx, w, false, StorageExhausted{})
 	}
+	if err := w.useConses(ctx, int64(size)); err != nil {
+		return nil, err
+	}
 	_list := make([]Node, size)
 	for i := range _list {
 		_list[i] = ini
@@ -275,7 +281,10 @@ func funArrayDimensions(ctx context.Context, w *World, arg Node) (Node, error) {
 	return cons, nil
 }
 
-func funVector(_ context.Context, w *World, args []Node) (Node, error) {
+func funVector(ctx context.Context, w *World, args []Node) (Node, error) {
+	if err := w.useConses(ctx, int64(len(args))); err != nil {
+		return nil, err
+	}
 	return &Array{
 		list: args,
 		dim:  []int{len(args)},
@@ -3,6 +3,7 @@ package gmnlisp
 import (
 	"context"
 	"fmt"
+	"sync"
 	"unicode"
 )
 
@@ -55,12 +56,30 @@ func (nt _NullType) Id() int {
 
 var Null Node = _NullType{}
 
+// idMap is shared by all the Worlds, so that it is guarded by mu.
 type idMap[T ~int] struct {
+	mu      sync.RWMutex
 	id2name []string
 	name2id map[string]T
+	// newCount is the count given to the format of newId. It advances
+	// even when the name made with it is used already.
+	newCount int
 }
 
 func (idm *idMap[T]) NameToId(name string) T {
+	idm.mu.RLock()
+	id, ok := idm.name2id[name]
+	idm.mu.RUnlock()
+	if ok {
+		return id
+	}
+	idm.mu.Lock()
+	defer idm.mu.Unlock()
+	return idm.add(name)
+}
+
+// add must be called with mu locked.
+func (idm *idMap[T]) add(name string) T {
 	if idm.name2id == nil {
 		idm.name2id = make(map[string]T)
 	}
@@ -73,7 +92,22 @@ func (idm *idMap[T]) NameToId(name string) T {
 	return id
 }
 
+// newId returns the new id whose name is made by format with the count.
+func (idm *idMap[T]) newId(format string) T {
+	idm.mu.Lock()
+	defer idm.mu.Unlock()
+	for {
+		name := fmt.Sprintf(format, idm.newCount)
+		idm.newCount++
+		if _, ok := idm.name2id[name]; !ok {
+			return idm.add(name)
+		}
+	}
+}
+
 func (idm *idMap[T]) Count() int {
+	idm.mu.RLock()
+	defer idm.mu.RUnlock()
 	return len(idm.name2id)
 }
 
@@ -89,6 +123,8 @@ func (s _Symbol) Id() int {
 }
 
 func (idm *idMap[T]) IdToName(id T) string {
+	idm.mu.RLock()
+	defer idm.mu.RUnlock()
 	if id < 0 || int(id) >= len(idm.id2name) {
 		return "(undefined)"
 	}
@@ -102,7 +138,7 @@ func NewSymbol(s string) _Symbol {
 }
 
 func genSym() _Symbol {
-	return NewSymbol(fmt.Sprintf("-gensym-%d-", symbolManager.Count()))
+	return symbolManager.newId("-gensym-%d-")
 }
 
 func funGensym(ctx context.Context, w *World) (Node, error) {
@@ -261,7 +297,7 @@ func funRuneIndex(ctx context.Context, w *World, argv []Node) (Node, error) {
 
 type Keyword int
 
-var keywordManager = idMap[Keyword]{}
+var keywordManager = &idMap[Keyword]{}
 
 func NewKeyword(name string) Keyword {
 	return keywordManager.NameToId(name)
@@ -0,0 +1,148 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Frame is one of the active calls of the functions, generic functions
+// and macros defined in Lisp.
+type Frame struct {
+	Name Symbol
+	Args []Node
+	// Pos is the position of the form which made the call. It is nil when unknown.
+	Pos *Position
+}
+
+func (f Frame) PrintTo(w io.Writer, m PrintMode) (int, error) {
+	var wc writeCounter
+	if wc.Try(io.WriteString(w, "(")) {
+		return wc.Result()
+	}
+	if f.Name == nulSymbol {
+		if wc.Try(io.WriteString(w, "lambda")) {
+			return wc.Result()
+		}
+	} else if wc.Try(tryPrintTo(w, f.Name, PRINC)) {
+		return wc.Result()
+	}
+	for _, arg := range f.Args {
+		if wc.Try(io.WriteString(w, " ")) || wc.Try(tryPrintTo(w, arg, m)) {
+			return wc.Result()
+		}
+	}
+	if wc.Try(io.WriteString(w, ")")) {
+		return wc.Result()
+	}
+	if f.Pos != nil {
+		wc.Try(io.WriteString(w, " at "+f.Pos.String()))
+	}
+	return wc.Result()
+}
+
+func (f Frame) String() string {
+	var buffer strings.Builder
+	f.PrintTo(&buffer, PRINT)
+	return buffer.String()
+}
+
+// Backtrace is the error annotated with the calls which were active
+// when Err occurred.
+type Backtrace struct {
+	Err error
+	// Frames are the active calls. The innermost call is the first.
+	Frames []Frame
+}
+
+// backtraceEnds is the number of the innermost and the outermost frames
+// which Error prints. The frames between them are omitted.
+const backtraceEnds = 10
+
+func (b *Backtrace) Error() string {
+	var buffer strings.Builder
+	buffer.WriteString(b.Err.Error())
+	for i, f := range b.Frames {
+		if i == backtraceEnds && len(b.Frames) > 2*backtraceEnds {
+			fmt.Fprintf(&buffer, "\n\t... %d more frames", len(b.Frames)-2*backtraceEnds)
+		}
+		if i >= backtraceEnds && i < len(b.Frames)-backtraceEnds {
+			continue
+		}
+		buffer.WriteString("\n\tcalled from ")
+		f.PrintTo(&buffer, PRINT)
+	}
+	return buffer.String()
+}
+
+func (b *Backtrace) Unwrap() error {
+	return b.Err
+}
+
+// enterFrame pushes the call of name with args and returns the depth
+// which has to be given to leaveFrame.
+func (w *World) enterFrame(name Symbol, args []Node, pos *Position) int {
+	depth := len(w.frames)
+	w.frames = append(w.frames, Frame{Name: name, Args: args, Pos: pos})
+	return depth
+}
+
+// leaveFrame pops the calls until depth. When err is the first error
+// leaving the calls, it is wrapped with the Backtrace.
+func (w *World) leaveFrame(depth int, err error) error {
+	if err != nil && !IsNonLocalExists(err) {
+		var bt *Backtrace
+		if !errors.As(err, &bt) {
+			err = &Backtrace{Err: err, Frames: w.Backtrace()}
+		}
+	}
+	w.frames = w.frames[:depth]
+	return err
+}
+
+// Backtrace returns the active calls. The innermost call is the first.
+// The arguments are copied because those of the bytecode are on the stack
+// reused by the later calls.
+func (w *World) Backtrace() []Frame {
+	frames := make([]Frame, len(w.frames))
+	for i, f := range w.frames {
+		f.Args = append([]Node(nil), f.Args...)
+		frames[len(frames)-1-i] = f
+	}
+	return frames
+}
+
+// withBacktraceOf calls f with the active calls replaced by those when err
+// occurred, so that the handlers called after unwinding can see them.
+func (w *World) withBacktraceOf(err error, f func() error) error {
+	var bt *Backtrace
+	if !errors.As(err, &bt) {
+		return f()
+	}
+	save := w.frames
+	w.frames = make([]Frame, len(bt.Frames))
+	for i, f := range bt.Frames {
+		w.frames[len(bt.Frames)-1-i] = f
+	}
+	err = f()
+	w.frames = save
+	return err
+}
+
+var symLambda = NewSymbol("lambda")
+
+// funBacktrace implements (backtrace). It returns the list of the active
+// calls as (NAME ARGS...), the innermost first.
+func funBacktrace(ctx context.Context, w *World) (Node, error) {
+	var result ListBuilder
+	for _, f := range w.Backtrace() {
+		var name Node = f.Name
+		if f.Name == nulSymbol {
+			name = symLambda
+		}
+		result.Add(ctx, w, &Cons{Car: name, Cdr: List(f.Args...)})
+	}
+	return result.Sequence(), nil
+}
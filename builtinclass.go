@@ -2,6 +2,7 @@ package gmnlisp
 
 import (
 	"context"
+	"sync"
 )
 
 type Class interface {
@@ -17,6 +18,10 @@ type _BuiltInClass struct {
 	instanceP func(Node) bool
 	create    func() Node
 	super     []Class
+	// cpl is the class precedence list computed at the first use.
+	cpl     []Class
+	cplErr  error
+	cplOnce sync.Once
 }
 
 func (e *_BuiltInClass) InheritP(c Class) bool {
@@ -96,7 +101,7 @@ func newAbstractClass[T Node](name string) *_BuiltInClass {
 	}
 }
 
-func registerClass(class *_BuiltInClass, super ...Class) Class {
+func registerClass(class *_BuiltInClass, super ...Class) *_BuiltInClass {
 	class.super = append(super, objectClass, builtInClass)
 	autoLoadVars[class.name] = class
 	return class
@@ -0,0 +1,731 @@
+package gmnlisp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// _OpCode is the operation of the stack machine which runs the body of
+// the lambda compiled by (compile).
+type _OpCode uint8
+
+const (
+	opConst           _OpCode = iota // push constants[a]
+	opLocal                          // push the variable b of the a-th outer frame
+	opSetLocal                       // set the top to the variable b of the a-th outer frame
+	opGlobal                         // push the variable constants[a]
+	opSetGlobal                      // set the top to the variable constants[a]
+	opPop                            // drop the top
+	opJump                           // go to a
+	opJumpIfNil                      // pop and go to a if it is nil
+	opJumpIfNilOrPop                 // go to a if the top is nil, otherwise pop
+	opJumpIfSomeOrPop                // go to a if the top is not nil, otherwise pop
+	opFunction                       // push the function of the form constants[a] or call it and go to b
+	opCallable                       // pop the function designated by the top and push it as opFunction
+	opCall                           // call the last function pushed with the b arguments for the form constants[a]
+	opTailCall                       // opCall at the tail position
+	opBinary                         // opCall with 2 arguments, which runs binaryOps[b] for its function
+	opMacro                          // go to b if the macro of the form constants[a] is redefined
+	opInterpret                      // push the value of constants[a] by the interpreter
+	opLet                            // make the frame of the names frames[a] from the values popped
+	opLetX                           // make the frame of the names frames[a] without values
+	opBind                           // pop and bind the next variable of the current frame
+	opLeave                          // leave the current frame
+	opCase                           // go to b unless the top is one of constants[a], otherwise pop
+	opBlock                          // push the value of blocks[b] run in the block named constants[a]
+	opReturn                         // return the top
+)
+
+var opNames = [...]string{
+	opConst:           "const",
+	opLocal:           "local",
+	opSetLocal:        "set-local",
+	opGlobal:          "global",
+	opSetGlobal:       "set-global",
+	opPop:             "pop",
+	opJump:            "jump",
+	opJumpIfNil:       "jump-if-nil",
+	opJumpIfNilOrPop:  "jump-if-nil-or-pop",
+	opJumpIfSomeOrPop: "jump-if-some-or-pop",
+	opFunction:        "function",
+	opCallable:        "callable",
+	opCall:            "call",
+	opTailCall:        "tail-call",
+	opBinary:          "binary",
+	opMacro:           "macro",
+	opInterpret:       "interpret",
+	opLet:             "let",
+	opLetX:            "let*",
+	opBind:            "bind",
+	opLeave:           "leave",
+	opCase:            "case",
+	opBlock:           "block",
+	opReturn:          "return",
+}
+
+// countsStep is true for the instructions of the special forms, which count
+// the steps of Limits as the special forms evaluated by the interpreter do.
+var countsStep = [opReturn + 1]bool{
+	opSetLocal:        true,
+	opSetGlobal:       true,
+	opJump:            true,
+	opJumpIfNil:       true,
+	opJumpIfNilOrPop:  true,
+	opJumpIfSomeOrPop: true,
+	opLet:             true,
+	opLetX:            true,
+	opCase:            true,
+	opBlock:           true,
+}
+
+func (op _OpCode) String() string {
+	if int(op) < len(opNames) {
+		return opNames[op]
+	}
+	return fmt.Sprintf("op%d", int(op))
+}
+
+type _Instruction struct {
+	op _OpCode
+	a  int32
+	b  int32
+}
+
+// _Program is the body of a lambda compiled into the bytecode.
+type _Program struct {
+	// scopes are the names of the frames outside the program, which are
+	// shown by the disassembler.
+	scopes    [][]Symbol
+	code      []_Instruction
+	constants []Node
+	frames    [][]Symbol
+	// blocks are the bodies of the blocks, which run as other programs
+	// to catch return-from.
+	blocks []*_Program
+	// sites are the functions found by the opFunction at the same index
+	// of code.
+	sites []_CallSite
+}
+
+// assembler translates the forms into a _Program. The forms which it
+// does not know are evaluated by the interpreter at runtime.
+type assembler struct {
+	w       *World
+	scope   *lexicalScope
+	program *_Program
+}
+
+type specialAssembler func(a *assembler, ctx context.Context, cons *Cons, tail bool) bool
+
+// specialAssemblers return false without emitting any instruction when
+// the form is malformed, so that the interpreter reports the error.
+var specialAssemblers map[uintptr]specialAssembler
+
+func init() {
+	specialAssemblers = map[uintptr]specialAssembler{
+		specialKey(cmdQuote):   (*assembler).quote,
+		specialKey(cmdIf):      (*assembler).ifForm,
+		specialKey(cmdProgn):   (*assembler).prognForm,
+		specialKey(cmdLet):     (*assembler).let,
+		specialKey(cmdLetX):    (*assembler).letX,
+		specialKey(cmdSetq):    (*assembler).setq,
+		specialKey(cmdCond):    (*assembler).cond,
+		specialKey(cmdCase):    (*assembler).caseForm,
+		specialKey(cmdAnd):     (*assembler).and,
+		specialKey(cmdOr):      (*assembler).or,
+		specialKey(cmdWhile):   (*assembler).while,
+		specialKey(cmdFunCall): (*assembler).funcall,
+		specialKey(cmdBlock):   (*assembler).block,
+	}
+}
+
+// assemble compiles the body of L into the bytecode.
+func assemble(ctx context.Context, L *_Lambda) *_Program {
+	a := &assembler{
+		w:       L.lexical,
+		scope:   &lexicalScope{names: L.names},
+		program: &_Program{scopes: [][]Symbol{L.names}},
+	}
+	if !a.progn(ctx, L.code, true) {
+		a.program.code = a.program.code[:0]
+		a.emit(opInterpret, a.constant(&Cons{Car: NewSymbol("progn"), Cdr: L.code}), 0)
+	}
+	return a.finish()
+}
+
+// finish appends the last instruction and returns the program.
+func (a *assembler) finish() *_Program {
+	a.emit(opReturn, 0, 0)
+	a.program.sites = make([]_CallSite, len(a.program.code))
+	return a.program
+}
+
+func (a *assembler) emit(op _OpCode, x, y int) int {
+	a.program.code = append(a.program.code, _Instruction{op: op, a: int32(x), b: int32(y)})
+	return len(a.program.code) - 1
+}
+
+func (a *assembler) constant(value Node) int {
+	a.program.constants = append(a.program.constants, value)
+	return len(a.program.constants) - 1
+}
+
+// here returns the address of the next instruction.
+func (a *assembler) here() int {
+	return len(a.program.code)
+}
+
+// patch makes the jump at the address go to the next instruction.
+func (a *assembler) patch(at int) {
+	if op := a.program.code[at].op; op == opFunction || op == opMacro || op == opCase {
+		a.program.code[at].b = int32(a.here())
+	} else {
+		a.program.code[at].a = int32(a.here())
+	}
+}
+
+func (a *assembler) form(ctx context.Context, node Node, tail bool) {
+	switch v := node.(type) {
+	case _Symbol:
+		if depth, index, ok := a.scope.resolve(v); ok {
+			a.emit(opLocal, depth, index)
+		} else {
+			a.emit(opGlobal, a.constant(v), 0)
+		}
+	case *Cons:
+		a.cons(ctx, v, tail)
+	case interface {
+		Eval(context.Context, *World) (Node, error)
+	}:
+		a.emit(opInterpret, a.constant(node), 0)
+	default:
+		a.emit(opConst, a.constant(node), 0)
+	}
+}
+
+func (a *assembler) cons(ctx context.Context, cons *Cons, tail bool) {
+	symbol, ok := cons.Car.(Symbol)
+	if !ok {
+		a.emit(opInterpret, a.constant(cons), 0)
+		return
+	}
+	if f, err := a.w.GetFunc(symbol); err == nil {
+		if sf, ok := f.(SpecialF); ok {
+			if sa, ok := specialAssemblers[specialKey(sf)]; !ok || !sa(a, ctx, cons, tail) {
+				a.emit(opInterpret, a.constant(cons), 0)
+			}
+			return
+		}
+		if m := macroOf(ctx, a.w, f); m != nil {
+			expanded, err := m.expand(ctx, a.w, cons.Cdr)
+			if err != nil {
+				a.emit(opInterpret, a.constant(cons), 0)
+				return
+			}
+			source := a.constant(cons)
+			a.constant(FunctionRef{value: f})
+			guard := a.emit(opMacro, source, 0)
+			a.form(ctx, expanded, tail)
+			end := a.emit(opJump, 0, 0)
+			a.patch(guard)
+			a.emit(opInterpret, source, 0)
+			a.patch(end)
+			return
+		}
+	}
+	forms, ok := properList(cons.Cdr)
+	if !ok {
+		a.emit(opInterpret, a.constant(cons), 0)
+		return
+	}
+	source := a.constant(cons)
+	function := a.emit(opFunction, source, 0)
+	for _, form := range forms {
+		a.form(ctx, form, false)
+	}
+	if op, ok := a.binaryOp(symbol, len(forms)); ok {
+		a.emit(opBinary, source, op)
+	} else {
+		a.call(source, len(forms), tail)
+	}
+	a.patch(function)
+}
+
+// _BinaryOp is the builtin function which the bytecode runs without the
+// call when it is given two arguments.
+type _BinaryOp struct {
+	function Callable
+	apply    func(context.Context, *World, Node, Node) (Node, error)
+	// test is true for the predicates which return t or nil.
+	test bool
+}
+
+var binaryOps []_BinaryOp
+
+func init() {
+	for _, op := range []struct {
+		name  string
+		apply func(context.Context, *World, Node, Node) (Node, error)
+		test  bool
+	}{
+		{name: "+", apply: plus},
+		{name: "-", apply: minus},
+		{name: "*", apply: times},
+		{name: "<", apply: lessThan, test: true},
+		{name: ">", apply: greaterThan, test: true},
+		{name: "<=", apply: lessOrEqual, test: true},
+		{name: ">=", apply: greaterOrEqual, test: true},
+		{name: "=", apply: numberEqual, test: true},
+	} {
+		binaryOps = append(binaryOps, _BinaryOp{
+			function: autoLoadFunc[NewSymbol(op.name)],
+			apply:    op.apply,
+			test:     op.test,
+		})
+	}
+}
+
+// binaryOp returns the index of binaryOps for the call of symbol with argc
+// arguments.
+func (a *assembler) binaryOp(symbol Symbol, argc int) (int, bool) {
+	if argc != 2 {
+		return 0, false
+	}
+	f, err := a.w.GetFunc(symbol)
+	if err != nil {
+		return 0, false
+	}
+	for i, op := range binaryOps {
+		if op.function == f {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (a *assembler) call(source, argc int, tail bool) {
+	if tail {
+		a.emit(opTailCall, source, argc)
+	} else {
+		a.emit(opCall, source, argc)
+	}
+}
+
+func (a *assembler) progn(ctx context.Context, list Node, tail bool) bool {
+	forms, ok := properList(list)
+	if !ok {
+		return false
+	}
+	if len(forms) == 0 {
+		a.emit(opConst, a.constant(Null), 0)
+		return true
+	}
+	for i, form := range forms {
+		if i > 0 {
+			a.emit(opPop, 0, 0)
+		}
+		a.form(ctx, form, tail && i == len(forms)-1)
+	}
+	return true
+}
+
+func (a *assembler) quote(_ context.Context, cons *Cons, _ bool) bool {
+	forms, ok := properList(cons.Cdr)
+	if !ok || len(forms) != 1 {
+		return false
+	}
+	a.emit(opConst, a.constant(forms[0]), 0)
+	return true
+}
+
+func (a *assembler) ifForm(ctx context.Context, cons *Cons, tail bool) bool {
+	forms, ok := properList(cons.Cdr)
+	if !ok || len(forms) < 2 || len(forms) > 3 {
+		return false
+	}
+	a.form(ctx, forms[0], false)
+	otherwise := a.emit(opJumpIfNil, 0, 0)
+	a.form(ctx, forms[1], tail)
+	end := a.emit(opJump, 0, 0)
+	a.patch(otherwise)
+	if len(forms) == 3 {
+		a.form(ctx, forms[2], tail)
+	} else {
+		a.emit(opConst, a.constant(Null), 0)
+	}
+	a.patch(end)
+	return true
+}
+
+func (a *assembler) prognForm(ctx context.Context, cons *Cons, tail bool) bool {
+	if _, ok := properList(cons.Cdr); !ok {
+		return false
+	}
+	return a.progn(ctx, cons.Cdr, tail)
+}
+
+// bindings parses the first argument of let and let*.
+func bindingForms(list Node) (names []Symbol, inits []Node, ok bool) {
+	items, ok := properList(list)
+	if !ok {
+		return nil, nil, false
+	}
+	for _, item := range items {
+		if symbol, ok := item.(Symbol); ok {
+			names = append(names, symbol)
+			inits = append(inits, Null)
+			continue
+		}
+		pair, ok := properList(item)
+		if !ok || len(pair) != 2 {
+			return nil, nil, false
+		}
+		symbol, ok := pair[0].(Symbol)
+		if !ok {
+			return nil, nil, false
+		}
+		names = append(names, symbol)
+		inits = append(inits, pair[1])
+	}
+	return names, inits, true
+}
+
+func (a *assembler) frame(names []Symbol) int {
+	a.program.frames = append(a.program.frames, names)
+	return len(a.program.frames) - 1
+}
+
+func (a *assembler) let(ctx context.Context, cons *Cons, tail bool) bool {
+	args, ok := cons.Cdr.(*Cons)
+	if !ok {
+		return false
+	}
+	names, inits, ok := bindingForms(args.Car)
+	if !ok {
+		return false
+	}
+	if _, ok := properList(args.Cdr); !ok {
+		return false
+	}
+	for _, init := range inits {
+		a.form(ctx, init, false)
+	}
+	a.emit(opLet, a.frame(names), 0)
+	a.scope = &lexicalScope{names: names, parent: a.scope}
+	a.progn(ctx, args.Cdr, tail)
+	a.scope = a.scope.parent
+	a.emit(opLeave, 0, 0)
+	return true
+}
+
+func (a *assembler) letX(ctx context.Context, cons *Cons, tail bool) bool {
+	args, ok := cons.Cdr.(*Cons)
+	if !ok {
+		return false
+	}
+	names, inits, ok := bindingForms(args.Car)
+	if !ok {
+		return false
+	}
+	if _, ok := properList(args.Cdr); !ok {
+		return false
+	}
+	a.emit(opLetX, a.frame(names), 0)
+	// each initform sees the variables bound before it.
+	a.scope = &lexicalScope{parent: a.scope}
+	for i, init := range inits {
+		a.form(ctx, init, false)
+		a.emit(opBind, 0, 0)
+		a.scope.names = names[:i+1]
+	}
+	a.progn(ctx, args.Cdr, tail)
+	a.scope = a.scope.parent
+	a.emit(opLeave, 0, 0)
+	return true
+}
+
+func (a *assembler) setq(ctx context.Context, cons *Cons, _ bool) bool {
+	forms, ok := properList(cons.Cdr)
+	if !ok || len(forms)%2 != 0 {
+		return false
+	}
+	for i := 0; i < len(forms); i += 2 {
+		if _, ok := forms[i].(Symbol); !ok {
+			return false
+		}
+	}
+	if len(forms) == 0 {
+		a.emit(opConst, a.constant(Null), 0)
+		return true
+	}
+	for i := 0; i < len(forms); i += 2 {
+		if i > 0 {
+			a.emit(opPop, 0, 0)
+		}
+		symbol := forms[i].(Symbol)
+		a.form(ctx, forms[i+1], false)
+		if depth, index, ok := a.scope.resolve(symbol); ok {
+			a.emit(opSetLocal, depth, index)
+		} else {
+			a.emit(opSetGlobal, a.constant(symbol), 0)
+		}
+	}
+	return true
+}
+
+func (a *assembler) cond(ctx context.Context, cons *Cons, tail bool) bool {
+	items, ok := properList(cons.Cdr)
+	if !ok {
+		return false
+	}
+	for _, item := range items {
+		clause, ok := item.(*Cons)
+		if !ok {
+			return false
+		}
+		if _, ok := properList(clause.Cdr); !ok {
+			return false
+		}
+	}
+	var ends []int
+	for _, item := range items {
+		clause := item.(*Cons)
+		a.form(ctx, clause.getCar(), false)
+		next := a.emit(opJumpIfNil, 0, 0)
+		a.progn(ctx, clause.Cdr, tail)
+		ends = append(ends, a.emit(opJump, 0, 0))
+		a.patch(next)
+	}
+	a.emit(opConst, a.constant(Null), 0)
+	for _, end := range ends {
+		a.patch(end)
+	}
+	return true
+}
+
+func (a *assembler) caseForm(ctx context.Context, cons *Cons, tail bool) bool {
+	items, ok := properList(cons.Cdr)
+	if !ok || len(items) < 1 {
+		return false
+	}
+	for _, item := range items[1:] {
+		clause, ok := item.(*Cons)
+		if !ok {
+			return false
+		}
+		if _, ok := properList(clause.Cdr); !ok {
+			return false
+		}
+		if keys, ok := clause.getCar().(*Cons); ok {
+			if _, ok := properList(keys); !ok {
+				return false
+			}
+		}
+	}
+	a.form(ctx, items[0], false)
+	var ends []int
+	otherwise := false
+	for _, item := range items[1:] {
+		clause := item.(*Cons)
+		if keys, ok := clause.getCar().(*Cons); ok {
+			next := a.emit(opCase, a.constant(keys), 0)
+			a.progn(ctx, clause.Cdr, tail)
+			ends = append(ends, a.emit(opJump, 0, 0))
+			a.patch(next)
+		} else if clause.getCar().Equals(True, STRICT) {
+			a.emit(opPop, 0, 0)
+			a.progn(ctx, clause.Cdr, tail)
+			otherwise = true
+			break
+		}
+	}
+	if !otherwise {
+		a.emit(opPop, 0, 0)
+		a.emit(opConst, a.constant(Null), 0)
+	}
+	for _, end := range ends {
+		a.patch(end)
+	}
+	return true
+}
+
+// andOr compiles and when jump is opJumpIfNilOrPop and or when it is
+// opJumpIfSomeOrPop.
+func (a *assembler) andOr(ctx context.Context, cons *Cons, tail bool, jump _OpCode, empty Node) bool {
+	forms, ok := properList(cons.Cdr)
+	if !ok {
+		return false
+	}
+	if len(forms) == 0 {
+		a.emit(opConst, a.constant(empty), 0)
+		return true
+	}
+	var ends []int
+	for i, form := range forms {
+		last := i == len(forms)-1
+		a.form(ctx, form, tail && last)
+		if !last {
+			ends = append(ends, a.emit(jump, 0, 0))
+		}
+	}
+	for _, end := range ends {
+		a.patch(end)
+	}
+	return true
+}
+
+func (a *assembler) and(ctx context.Context, cons *Cons, tail bool) bool {
+	return a.andOr(ctx, cons, tail, opJumpIfNilOrPop, True)
+}
+
+func (a *assembler) or(ctx context.Context, cons *Cons, tail bool) bool {
+	return a.andOr(ctx, cons, tail, opJumpIfSomeOrPop, Null)
+}
+
+func (a *assembler) while(ctx context.Context, cons *Cons, _ bool) bool {
+	args, ok := cons.Cdr.(*Cons)
+	if !ok {
+		return false
+	}
+	body, ok := properList(args.Cdr)
+	if !ok {
+		return false
+	}
+	// the value of the last body stays on the stack.
+	a.emit(opConst, a.constant(Null), 0)
+	top := a.here()
+	a.form(ctx, args.getCar(), false)
+	end := a.emit(opJumpIfNil, 0, 0)
+	a.emit(opPop, 0, 0)
+	if len(body) == 0 {
+		a.emit(opConst, a.constant(Null), 0)
+	}
+	for i, form := range body {
+		if i > 0 {
+			a.emit(opPop, 0, 0)
+		}
+		a.form(ctx, form, false)
+	}
+	a.emit(opJump, top, 0)
+	a.patch(end)
+	return true
+}
+
+func (a *assembler) block(ctx context.Context, cons *Cons, _ bool) bool {
+	args, ok := cons.Cdr.(*Cons)
+	if !ok {
+		return false
+	}
+	name, ok := blockName(args.Car)
+	if !ok {
+		return false
+	}
+	if _, ok := properList(args.Cdr); !ok {
+		return false
+	}
+	var scopes [][]Symbol
+	for s := a.scope; s != nil; s = s.parent {
+		scopes = append(scopes, s.names)
+	}
+	body := &assembler{
+		w:       a.w,
+		scope:   a.scope,
+		program: &_Program{scopes: scopes},
+	}
+	body.progn(ctx, args.Cdr, false)
+	a.program.blocks = append(a.program.blocks, body.finish())
+	a.emit(opBlock, a.constant(name), len(a.program.blocks)-1)
+	return true
+}
+
+func (a *assembler) funcall(ctx context.Context, cons *Cons, tail bool) bool {
+	forms, ok := properList(cons.Cdr)
+	if !ok || len(forms) < 1 {
+		return false
+	}
+	a.form(ctx, forms[0], false)
+	a.emit(opCallable, 0, 0)
+	for _, form := range forms[1:] {
+		a.form(ctx, form, false)
+	}
+	a.call(a.constant(cons), len(forms)-1, tail)
+	return true
+}
+
+// disassemble writes the instructions of p. The blocks follow the
+// instructions running them with the indent.
+func (p *_Program) disassemble(w io.Writer, indent string) {
+	for pc, inst := range p.code {
+		var operands string
+		switch inst.op {
+		case opConst, opGlobal, opSetGlobal, opInterpret:
+			operands = fmt.Sprintf("%d\t; %s", inst.a, printString(p.constants[inst.a]))
+		case opLocal, opSetLocal:
+			operands = fmt.Sprintf("%d %d", inst.a, inst.b)
+			if name := p.localName(pc, inst); name != "" {
+				operands += "\t; " + name
+			}
+		case opJump, opJumpIfNil, opJumpIfNilOrPop, opJumpIfSomeOrPop:
+			operands = fmt.Sprint(inst.a)
+		case opFunction, opMacro, opCall, opTailCall, opBinary:
+			operands = fmt.Sprintf("%d %d\t; %s", inst.a, inst.b, printString(p.head(inst.a)))
+		case opCase, opBlock:
+			operands = fmt.Sprintf("%d %d\t; %s", inst.a, inst.b, printString(p.constants[inst.a]))
+		case opLet, opLetX:
+			names := make([]string, len(p.frames[inst.a]))
+			for i, name := range p.frames[inst.a] {
+				names[i] = name.String()
+			}
+			operands = fmt.Sprintf("%d\t; (%s)", inst.a, strings.Join(names, " "))
+		}
+		fmt.Fprintf(w, "%s%4d  %-20s%s\n", indent, pc, inst.op, operands)
+		if inst.op == opBlock {
+			p.blocks[inst.b].disassemble(w, indent+"      ")
+		}
+	}
+}
+
+func printString(node Node) string {
+	var buffer strings.Builder
+	tryPrintTo(&buffer, node, PRINT)
+	return buffer.String()
+}
+
+// head returns the symbol of the form constants[i].
+func (p *_Program) head(i int32) Node {
+	if cons, ok := p.constants[i].(*Cons); ok {
+		if symbol, ok := cons.Car.(Symbol); ok {
+			return symbol
+		}
+	}
+	return p.constants[i]
+}
+
+// localName returns the name of the variable which the instruction at pc
+// reads or writes by tracing the frames entered before it.
+func (p *_Program) localName(pc int, inst _Instruction) string {
+	var frames []int
+	for _, prev := range p.code[:pc] {
+		switch prev.op {
+		case opLet, opLetX:
+			frames = append(frames, int(prev.a))
+		case opLeave:
+			if len(frames) > 0 {
+				frames = frames[:len(frames)-1]
+			}
+		}
+	}
+	depth := int(inst.a)
+	var names []Symbol
+	if depth < len(frames) {
+		names = p.frames[frames[len(frames)-1-depth]]
+	} else if depth-len(frames) < len(p.scopes) {
+		names = p.scopes[depth-len(frames)]
+	}
+	if int(inst.b) < len(names) {
+		return names[inst.b].String()
+	}
+	return ""
+}
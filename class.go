@@ -2,9 +2,12 @@ package gmnlisp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"sort"
 	"strings"
+	"sync/atomic"
 )
 
 type _SlotSpec struct {
@@ -20,7 +23,7 @@ type _SlotSpec struct {
 func newGetter(class Class, slotName Symbol) *_Method {
 	return &_Method{
 		types: []Class{class},
-		method: func(ctx context.Context, w *World, node []Node) (Node, error) {
+		method: func(ctx context.Context, w *World, node []Node, _ _NextMethod) (Node, error) {
 			rec, ok := node[0].(*_StandardObject)
 			if !ok {
 				return nil, fmt.Errorf("%v: %w", node[0], ErrExpectedClass)
@@ -36,7 +39,7 @@ func newGetter(class Class, slotName Symbol) *_Method {
 func newSetter(class Class, slotName Symbol) *_Method {
 	return &_Method{
 		types: []Class{objectClass, class},
-		method: func(ctx context.Context, w *World, node []Node) (Node, error) {
+		method: func(ctx context.Context, w *World, node []Node, _ _NextMethod) (Node, error) {
 			rec, ok := node[1].(*_StandardObject)
 			if !ok {
 				return nil, fmt.Errorf("%v: %w", node[1], ErrExpectedClass)
@@ -50,7 +53,7 @@ func newSetter(class Class, slotName Symbol) *_Method {
 func newBoundp(class Class, slotName Symbol) *_Method {
 	return &_Method{
 		types: []Class{class},
-		method: func(ctx context.Context, w *World, node []Node) (Node, error) {
+		method: func(ctx context.Context, w *World, node []Node, _ _NextMethod) (Node, error) {
 			rec, ok := node[0].(*_StandardObject)
 			if !ok {
 				return nil, fmt.Errorf("%v: %w", node[0], ErrExpectedClass)
@@ -66,12 +69,12 @@ func newBoundp(class Class, slotName Symbol) *_Method {
 func registerMethod(w *World, methodName Symbol, class Class, method *_Method) error {
 	if _acc, err := w.GetFunc(methodName); err == nil {
 		if gen, ok := _acc.(*_Generic); ok {
-			gen.methods = append(gen.methods, method)
+			gen.addMethod(method)
 		} else {
 			return fmt.Errorf("%v: already defined as not method", methodName)
 		}
 	} else {
-		w.defun.Set(methodName, &_Generic{
+		w.defineFunc(methodName, &_Generic{
 			Symbol:  methodName,
 			argc:    len(method.types),
 			rest:    method.restType != nil,
@@ -164,6 +167,429 @@ type _StandardClass struct {
 	Symbol Symbol
 	Super  []Class
 	Slot   map[Symbol]*_SlotSpec
+
+	// slotOrder is the names of Slot in the order of the definition.
+	slotOrder []Symbol
+	// cpl is the class precedence list which begins with the class itself.
+	cpl []Class
+	// slots are the slots merged from all the classes in cpl.
+	slots []*_SlotSpec
+	// world is where the class is defined. print-object is looked up in it.
+	world *World
+}
+
+func directSuperclasses(class Class) []Class {
+	switch c := class.(type) {
+	case *_BuiltInClass:
+		// <built-in-class> is the metaclass of the built-in classes, but
+		// it is in their super so that InheritP finds it.
+		supers := make([]Class, 0, len(c.super))
+		for _, s := range c.super {
+			if s != builtInClass {
+				supers = append(supers, s)
+			}
+		}
+		return supers
+	case *_StandardClass:
+		return c.Super
+	}
+	return nil
+}
+
+func indexOfClass(classes []Class, class Class) int {
+	for i, c := range classes {
+		if c.Equals(class, STRICT) {
+			return i
+		}
+	}
+	return -1
+}
+
+var errInconsistentPrecedence = errors.New("inconsistent class precedence")
+
+// linearize computes the class precedence list of class with the C3
+// linearization. Each class precedes its super classes, and the order of the
+// direct super classes is kept in every list.
+func linearize(class Class) ([]Class, error) {
+	switch c := class.(type) {
+	case *_StandardClass:
+		if c.cpl != nil {
+			return c.cpl, nil
+		}
+	case *_BuiltInClass:
+		// The built-in classes are shared by the Worlds running in parallel.
+		c.cplOnce.Do(func() {
+			c.cpl, c.cplErr = mergeSupers(class)
+		})
+		return c.cpl, c.cplErr
+	}
+	return mergeSupers(class)
+}
+
+// mergeSupers merges the class precedence lists of the direct super classes
+// of class.
+func mergeSupers(class Class) ([]Class, error) {
+	supers := directSuperclasses(class)
+	lists := make([][]Class, 0, len(supers)+1)
+	for _, s := range supers {
+		cpl, err := linearize(s)
+		if err != nil {
+			return nil, err
+		}
+		lists = append(lists, cpl)
+	}
+	lists = append(lists, supers)
+
+	result := []Class{class}
+	for {
+		var candidate Class
+		empty := true
+		for _, list := range lists {
+			if len(list) <= 0 {
+				continue
+			}
+			empty = false
+			inTail := false
+			for _, other := range lists {
+				if len(other) > 0 && indexOfClass(other[1:], list[0]) >= 0 {
+					inTail = true
+					break
+				}
+			}
+			if !inTail {
+				candidate = list[0]
+				break
+			}
+		}
+		if empty {
+			return result, nil
+		}
+		if candidate == nil {
+			return nil, fmt.Errorf("%s: %w", class.Name(), errInconsistentPrecedence)
+		}
+		result = append(result, candidate)
+		for i, list := range lists {
+			if len(list) > 0 && list[0].Equals(candidate, STRICT) {
+				lists[i] = list[1:]
+			}
+		}
+	}
+}
+
+// classPrecedenceList returns class and its super classes ordered from
+// the most specific one. <object> is always included.
+func classPrecedenceList(class Class) ([]Class, error) {
+	cpl, err := linearize(class)
+	if err != nil {
+		return nil, err
+	}
+	if indexOfClass(cpl, objectClass) < 0 {
+		cpl = append(cpl[:len(cpl):len(cpl)], objectClass)
+	}
+	return cpl, nil
+}
+
+// mergeSlots returns the slots of all the classes in c.cpl. The slots of the
+// same name are merged into one whose initform is the most specific one.
+func (c *_StandardClass) mergeSlots() []*_SlotSpec {
+	var slots []*_SlotSpec
+	index := map[Symbol]int{}
+	for i := len(c.cpl) - 1; i >= 0; i-- {
+		class, ok := c.cpl[i].(*_StandardClass)
+		if !ok {
+			continue
+		}
+		for _, name := range class.slotOrder {
+			spec := class.Slot[name]
+			j, ok := index[name]
+			if !ok {
+				index[name] = len(slots)
+				copied := *spec
+				slots = append(slots, &copied)
+				continue
+			}
+			merged := slots[j]
+			if spec.initform != nil {
+				merged.initform = spec.initform
+			}
+			merged.initarg = appendSymbols(merged.initarg, spec.initarg)
+			merged.reader = appendSymbols(merged.reader, spec.reader)
+			merged.writer = appendSymbols(merged.writer, spec.writer)
+			merged.accessor = appendSymbols(merged.accessor, spec.accessor)
+			merged.boundp = appendSymbols(merged.boundp, spec.boundp)
+		}
+	}
+	return slots
+}
+
+func appendSymbols(list []Symbol, symbols []Symbol) []Symbol {
+	result := append([]Symbol{}, list...)
+	for _, s := range symbols {
+		found := false
+		for _, s1 := range result {
+			if s1 == s {
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+func funClassPrecedenceList(ctx context.Context, w *World, arg Node) (Node, error) {
+	class, err := ExpectInterface[Class](ctx, w, arg, classClass)
+	if err != nil {
+		return nil, err
+	}
+	cpl, err := classPrecedenceList(class)
+	if err != nil {
+		return raiseProgramError(ctx, w, err)
+	}
+	var result ListBuilder
+	for _, c := range cpl {
+		result.Add(ctx, w, c)
+	}
+	return result.Sequence(), nil
+}
+
+// StandardClass is the class defined with defclass or DefineStruct. The
+// classes returned by FindClass and Classes can be asserted to it.
+type StandardClass interface {
+	Class
+	// DirectSuperclasses returns the classes given to defclass as the super classes.
+	DirectSuperclasses() []Class
+	// PrecedenceList returns the class precedence list beginning with the class.
+	PrecedenceList() []Class
+	// Slots returns the slots of the class including the inherited ones.
+	Slots() []SlotDefinition
+}
+
+var _ StandardClass = &_StandardClass{}
+
+// StandardObject is the instance of a StandardClass. The values created by
+// create or by the classes of DefineStruct can be asserted to it.
+type StandardObject interface {
+	Node
+	// SlotValue returns the value of the slot named name. The second value
+	// is false when the slot is unbound or does not exist.
+	SlotValue(name Symbol) (Node, bool)
+	// SetSlotValue sets value to the slot named name.
+	SetSlotValue(name Symbol, value Node) error
+}
+
+var _ StandardObject = &_StandardObject{}
+
+// SlotDefinition describes a slot of the class defined with defclass.
+type SlotDefinition struct {
+	Name      Symbol
+	InitArgs  []Symbol
+	Readers   []Symbol
+	Writers   []Symbol
+	Accessors []Symbol
+	Boundp    []Symbol
+	// InitForm is true when the slot has :initform.
+	InitForm bool
+}
+
+// DirectSuperclasses returns the classes given to defclass as the super classes.
+func (c *_StandardClass) DirectSuperclasses() []Class {
+	return append([]Class{}, c.Super...)
+}
+
+// PrecedenceList returns the class precedence list beginning with c.
+func (c *_StandardClass) PrecedenceList() []Class {
+	return append([]Class{}, c.cpl...)
+}
+
+// Slots returns the slots of c including the inherited ones.
+func (c *_StandardClass) Slots() []SlotDefinition {
+	slots := make([]SlotDefinition, 0, len(c.slots))
+	for _, s := range c.slots {
+		slots = append(slots, SlotDefinition{
+			Name:      s.identifier,
+			InitArgs:  append([]Symbol{}, s.initarg...),
+			Readers:   append([]Symbol{}, s.reader...),
+			Writers:   append([]Symbol{}, s.writer...),
+			Accessors: append([]Symbol{}, s.accessor...),
+			Boundp:    append([]Symbol{}, s.boundp...),
+			InitForm:  s.initform != nil,
+		})
+	}
+	return slots
+}
+
+func (c *_StandardClass) findSlot(name Symbol) *_SlotSpec {
+	for _, s := range c.slots {
+		if s.identifier == name {
+			return s
+		}
+	}
+	return nil
+}
+
+var (
+	symClass = NewSymbol("class")
+	symSlot  = NewSymbol("slot")
+)
+
+// FindClass returns the class named name.
+func (w *World) FindClass(name Symbol) (Class, error) {
+	value, err := w.Get(name)
+	if err == nil {
+		if class, ok := value.(Class); ok {
+			return class, nil
+		}
+	}
+	return nil, &_UndefinedEntity{name: name, space: symClass}
+}
+
+// Classes returns the classes defined with defclass sorted by their names.
+func (w *World) Classes() []Class {
+	var classes []Class
+	w.Range(func(_ Symbol, value Node) bool {
+		if class, ok := value.(*_StandardClass); ok {
+			classes = append(classes, class)
+		}
+		return true
+	})
+	sort.Slice(classes, func(i, j int) bool {
+		return classes[i].Name().String() < classes[j].Name().String()
+	})
+	return classes
+}
+
+func symbolList(symbols []Symbol) Node {
+	var result Node = Null
+	for i := len(symbols) - 1; i >= 0; i-- {
+		result = &Cons{Car: symbols[i], Cdr: result}
+	}
+	return result
+}
+
+func funFindClass(ctx context.Context, w *World, arg Node) (Node, error) {
+	name, err := ExpectSymbol(ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	class, err := w.FindClass(name)
+	if err != nil {
+		return callHandler[Class](ctx, w, false, err.(*_UndefinedEntity))
+	}
+	return class, nil
+}
+
+func funClassName(ctx context.Context, w *World, arg Node) (Node, error) {
+	class, err := ExpectInterface[Class](ctx, w, arg, classClass)
+	if err != nil {
+		return nil, err
+	}
+	return class.Name(), nil
+}
+
+func funClassDirectSuperclasses(ctx context.Context, w *World, arg Node) (Node, error) {
+	class, err := ExpectInterface[Class](ctx, w, arg, classClass)
+	if err != nil {
+		return nil, err
+	}
+	var result ListBuilder
+	for _, s := range directSuperclasses(class) {
+		result.Add(ctx, w, s)
+	}
+	return result.Sequence(), nil
+}
+
+// funClassSlots implements (class-slots CLASS). Each slot is represented as
+// (NAME :initarg (...) :reader (...) :writer (...) :accessor (...) :boundp (...)).
+func funClassSlots(ctx context.Context, w *World, arg Node) (Node, error) {
+	class, err := ExpectInterface[Class](ctx, w, arg, classClass)
+	if err != nil {
+		return nil, err
+	}
+	c, ok := class.(*_StandardClass)
+	if !ok {
+		return Null, nil
+	}
+	var result ListBuilder
+	for _, s := range c.Slots() {
+		result.Add(ctx, w, List(s.Name,
+			kwInitArg, symbolList(s.InitArgs),
+			kwReader, symbolList(s.Readers),
+			kwWriter, symbolList(s.Writers),
+			kwAccessor, symbolList(s.Accessors),
+			kwBoundp, symbolList(s.Boundp)))
+	}
+	return result.Sequence(), nil
+}
+
+// SlotValue returns the value of the slot named name. The second value is
+// false when the slot is unbound or does not exist.
+func (o *_StandardObject) SlotValue(name Symbol) (Node, bool) {
+	value, ok := o.Slot[name]
+	return value, ok
+}
+
+// SetSlotValue sets value to the slot named name.
+func (o *_StandardObject) SetSlotValue(name Symbol, value Node) error {
+	if o._StandardClass.findSlot(name) == nil {
+		return fmt.Errorf("%s: %w", name.String(), errNoSuchSlot)
+	}
+	o.Slot[name] = value
+	return nil
+}
+
+var errNoSuchSlot = errors.New("no such slot")
+
+func expectSlot(ctx context.Context, w *World, obj, name Node) (*_StandardObject, Symbol, error) {
+	o, err := ExpectInterface[*_StandardObject](ctx, w, obj, standardObjectClass)
+	if err != nil {
+		return nil, nil, err
+	}
+	slotName, err := ExpectSymbol(ctx, w, name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if o._StandardClass.findSlot(slotName) == nil {
+		_, err := raiseProgramError(ctx, w, fmt.Errorf("%s: %w", slotName.String(), errNoSuchSlot))
+		return nil, nil, err
+	}
+	return o, slotName, nil
+}
+
+func funSlotValue(ctx context.Context, w *World, obj, name Node) (Node, error) {
+	o, slotName, err := expectSlot(ctx, w, obj, name)
+	if err != nil {
+		return nil, err
+	}
+	if value, ok := o.SlotValue(slotName); ok {
+		return value, nil
+	}
+	return callHandler[Node](ctx, w, false, &_UndefinedEntity{
+		name:  slotName,
+		space: symSlot,
+	})
+}
+
+func funSetSlotValue(ctx context.Context, w *World, args []Node) (Node, error) {
+	o, slotName, err := expectSlot(ctx, w, args[1], args[2])
+	if err != nil {
+		return nil, err
+	}
+	o.Slot[slotName] = args[0]
+	return args[0], nil
+}
+
+func funSlotBoundp(ctx context.Context, w *World, obj, name Node) (Node, error) {
+	o, slotName, err := expectSlot(ctx, w, obj, name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := o.SlotValue(slotName); ok {
+		return True, nil
+	}
+	return Null, nil
 }
 
 // standardClass can not be created with registerNewBuilInClass
@@ -175,8 +601,7 @@ var standardClass = &_BuiltInClass{
 		return ok
 	},
 	create: func() Node {
-		classCounter++
-		return &_StandardClass{}
+		return &_StandardClass{serial: int(classCounter.Add(1))}
 	},
 	super: []Class{objectClass},
 }
@@ -197,15 +622,15 @@ func (u *_StandardClass) Equals(_other Node, _ EqlMode) bool {
 }
 
 func (class1 *_StandardClass) InheritP(class2 Class) bool {
-	for _, s := range class1.Super {
-		if s.Equals(class2, STRICT) {
-			return true
-		}
-		if s.InheritP(class2) {
-			return true
+	if class1.cpl == nil {
+		for _, s := range class1.Super {
+			if s.Equals(class2, STRICT) || s.InheritP(class2) {
+				return true
+			}
 		}
+		return false
 	}
-	return false
+	return indexOfClass(class1.cpl[1:], class2) >= 0
 }
 
 func (c *_StandardClass) String() string {
@@ -219,31 +644,12 @@ func (c *_StandardClass) Name() Symbol {
 	return c.Symbol
 }
 
-// subClassP returns true when class1 is one of the sub-classes of class2
-func (class1 *_StandardClass) subClassP(class2 *_StandardClass) bool {
-	for _, super := range class1.Super {
-		if super.Name() == class2.Name() {
-			return true
-		}
-		if uc, ok := super.(*_StandardClass); ok && uc.subClassP(class2) {
-			return true
-		}
-	}
-	return false
-}
-
 func (c *_StandardClass) InstanceP(obj Node) bool {
-	class := obj.ClassOf()
-	userClass, ok := class.(*_StandardClass)
+	userClass, ok := obj.ClassOf().(*_StandardClass)
 	if !ok {
-		// println("InstanceP: NG1")
 		return false
 	}
-	if userClass.Symbol.Equals(c.Symbol, STRICT) {
-		return true
-	}
-	// println("InstanceP: NG2")
-	return userClass.subClassP(c)
+	return userClass.Equals(c, STRICT) || userClass.InheritP(c)
 }
 
 func (c *_StandardClass) Create() Node {
@@ -255,7 +661,8 @@ func (c *_StandardClass) Create() Node {
 
 var symInitializeObject = NewSymbol("initialize-object")
 
-var classCounter = 0
+// classCounter gives the serial numbers of the classes to all the Worlds.
+var classCounter atomic.Int64
 
 func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 	// (defclass class-name (sc-name*) (slot-spec*) class-opt*)
@@ -269,11 +676,11 @@ func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	classCounter++
 	class := &_StandardClass{
-		serial: classCounter,
+		serial: int(classCounter.Add(1)),
 		Symbol: className,
 		Slot:   make(map[Symbol]*_SlotSpec),
+		world:  w,
 	}
 	if IsNone(args) {
 		w.DefineGlobal(className, class)
@@ -296,7 +703,11 @@ func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 		}
 		class.Super = append(class.Super, super)
 	}
+	if class.cpl, err = classPrecedenceList(class); err != nil {
+		return raiseProgramError(ctx, w, err)
+	}
 	if IsNone(args) {
+		class.slots = class.mergeSlots()
 		w.DefineGlobal(className, class)
 		return className, nil
 	}
@@ -317,6 +728,9 @@ func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 		if err != nil {
 			return nil, fmt.Errorf("[3][%d] %w", slotCount, err)
 		}
+		if _, ok := class.Slot[spec.identifier]; !ok {
+			class.slotOrder = append(class.slotOrder, spec.identifier)
+		}
 		class.Slot[spec.identifier] = spec
 
 		getter := newGetter(class, spec.identifier)
@@ -347,6 +761,7 @@ func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 			}
 		}
 	}
+	class.slots = class.mergeSlots()
 	w.DefineGlobal(className, class)
 
 	registerMethod(w, symInitializeObject, class, &_Method{
@@ -357,6 +772,10 @@ func cmdDefClass(ctx context.Context, w *World, args Node) (Node, error) {
 	return className, nil
 }
 
+// standardObjectClass is the class of all the instances of the classes
+// defined with defclass.
+var standardObjectClass = registerNewAbstractClass[*_StandardObject]("<standard-object>")
+
 type _StandardObject struct {
 	_StandardClass *_StandardClass
 	Slot           map[Symbol]Node
@@ -388,7 +807,127 @@ func (c *_StandardObject) ClassOf() Class {
 	return c._StandardClass
 }
 
+var symPrintObject = NewSymbol("print-object")
+
+type printModeKey struct{}
+
+type countWriter struct {
+	io.Writer
+	n int
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.Writer.Write(p)
+	c.n += n
+	return n, err
+}
+
+// _PrintContext is the writer which carries the context and the World of
+// the caller printing, so that the methods of print-object can be called
+// with them.
+type _PrintContext struct {
+	io.Writer
+	ctx   context.Context
+	world *World
+}
+
+func (p *_PrintContext) Column() int {
+	if col, ok := p.Writer.(interface{ Column() int }); ok {
+		return col.Column()
+	}
+	return 0
+}
+
+// printTo prints node to out as tryPrintTo does. The instances of defclass
+// in node are printed by the methods of print-object called with ctx and w.
+func printTo(ctx context.Context, w *World, out io.Writer, node Node, mode PrintMode) (int, error) {
+	if _, ok := out.(*_PrintContext); !ok {
+		out = &_PrintContext{Writer: out, ctx: ctx, world: w}
+	}
+	return tryPrintTo(out, node, mode)
+}
+
+// Print writes node to out by the methods of print-object defined in w.
+// String and GoString of the instances of defclass call the methods in
+// the World where the class is defined with context.Background.
+func (w *World) Print(ctx context.Context, out io.Writer, node Node, mode PrintMode) (int, error) {
+	return printTo(ctx, w, out, node, mode)
+}
+
+// PrintTo calls the generic function print-object with the context and the
+// World of the caller when w is given by printTo. Otherwise it calls the
+// function in the World where the class is defined with
+// context.Background. The classes made from the Go structs use the World
+// of the caller. When no method is defined for the class, the slots are
+// printed.
 func (c *_StandardObject) PrintTo(w io.Writer, mode PrintMode) (int, error) {
+	world := c._StandardClass.world
+	ctx := context.Background()
+	if caller, ok := w.(*_PrintContext); ok && (world == nil || caller.world.shared == world.shared) {
+		ctx, world = caller.ctx, caller.world
+	}
+	if world == nil {
+		return c.printSlotsTo(w, mode)
+	}
+	f, ok := world.defun.Get(symPrintObject)
+	if !ok {
+		return c.printSlotsTo(w, mode)
+	}
+	gen, ok := f.(*_Generic)
+	if !ok {
+		return c.printSlotsTo(w, mode)
+	}
+	cw := &countWriter{Writer: w}
+	stream := &_WriterNode{_Writer: cw}
+	if col, ok := w.(interface{ Column() int }); ok {
+		stream.column = col.Column()
+	}
+	ctx = context.WithValue(ctx, printModeKey{}, mode)
+	args := []Node{c, stream}
+	_, ok, err := callMethods(ctx, world, gen.applicableMethods(args), args)
+	if !ok && err == nil {
+		return c.printSlotsTo(w, mode)
+	}
+	return cw.n, err
+}
+
+// defaultPrintObject is the method of print-object for <object>.
+// It prints ~S when called from Lisp and the slots for the standard objects.
+func defaultPrintObject(ctx context.Context, w *World, args []Node, _ _NextMethod) (Node, error) {
+	mode := PRINT
+	if m, ok := ctx.Value(printModeKey{}).(PrintMode); ok {
+		mode = m
+	}
+	type writerType interface {
+		Node
+		io.Writer
+	}
+	writer, err := ExpectInterface[writerType](ctx, w, args[1], streamClass)
+	if err != nil {
+		return nil, err
+	}
+	if obj, ok := args[0].(*_StandardObject); ok {
+		_, err = obj.printSlotsTo(writer, mode)
+	} else {
+		_, err = printTo(ctx, w, writer, args[0], mode)
+	}
+	return Null, err
+}
+
+func newPrintObject() *_Generic {
+	return &_Generic{
+		Symbol: symPrintObject,
+		argc:   2,
+		methods: []*_Method{
+			{
+				types:  []Class{objectClass, objectClass},
+				method: defaultPrintObject,
+			},
+		},
+	}
+}
+
+func (c *_StandardObject) printSlotsTo(w io.Writer, mode PrintMode) (int, error) {
 	n, err := tryPrintTo(w, c._StandardClass.Symbol, mode)
 	if err != nil {
 		return n, err
@@ -435,17 +974,10 @@ func (c *_StandardObject) GoString() string {
 }
 
 func (reciever *_StandardObject) callInitForm(classDef *_StandardClass) error {
-	for _, super := range classDef.Super {
-		if uc, ok := super.(*_StandardClass); ok {
-			if err := reciever.callInitForm(uc); err != nil {
-				return err
-			}
-		}
-	}
-	for name, slot1 := range classDef.Slot {
-		if _, ok := reciever.Slot[name]; !ok && slot1.initform != nil {
+	for _, slot1 := range classDef.slots {
+		if _, ok := reciever.Slot[slot1.identifier]; !ok && slot1.initform != nil {
 			var err error
-			reciever.Slot[name], err = slot1.initform()
+			reciever.Slot[slot1.identifier], err = slot1.initform()
 			if err != nil {
 				return err
 			}
@@ -455,22 +987,17 @@ func (reciever *_StandardObject) callInitForm(classDef *_StandardClass) error {
 }
 
 func (reciever *_StandardObject) callInitArg(classDef *_StandardClass, initArg Symbol, initVal Node) bool {
-	for name, slot := range classDef.Slot {
+	found := false
+	for _, slot := range classDef.slots {
 		for _, slotInitArg := range slot.initarg {
 			if slotInitArg == initArg {
-				reciever.Slot[name] = initVal
-				return true
-			}
-		}
-	}
-	for _, super := range classDef.Super {
-		if superUc, ok := super.(*_StandardClass); ok {
-			if reciever.callInitArg(superUc, initArg, initVal) {
-				return true
+				reciever.Slot[slot.identifier] = initVal
+				found = true
+				break
 			}
 		}
 	}
-	return false
+	return found
 }
 
 type Uneval struct {
@@ -516,7 +1043,7 @@ func funCreate(ctx context.Context, w *World, args []Node) (Node, error) {
 	return rec, nil
 }
 
-func defaultInitializeObject(ctx context.Context, w *World, args []Node) (Node, error) {
+func defaultInitializeObject(ctx context.Context, w *World, args []Node, _ _NextMethod) (Node, error) {
 	_this, args := args[0], args[1:]
 	this, ok := _this.(*_StandardObject)
 	if !ok {
@@ -2,7 +2,10 @@ package gmnlisp
 
 import (
 	"context"
+	"errors"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestDefClass(t *testing.T) {
@@ -51,3 +54,118 @@ func TestDefClass(t *testing.T) {
 		t.Fatalf("iniform is wrong: %#v", val)
 	}
 }
+
+func TestIntrospection(t *testing.T) {
+	w := New()
+	todo := context.TODO()
+	_, err := w.Interpret(todo, `
+		(defclass <shape> () ((name :initarg name :reader shape-name)))
+		(defclass <circle> (<shape>) ((r :accessor radius)))
+		(defgeneric area (s))
+		(defmethod area ((c <circle>)) (* 3 (radius c) (radius c)))`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	circle, err := w.FindClass(NewSymbol("<circle>"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	found := false
+	for _, c := range w.Classes() {
+		if c.Equals(circle, STRICT) {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("Classes() does not contain <circle>")
+	}
+	standard, ok := circle.(StandardClass)
+	if !ok {
+		t.Fatalf("%v is not a StandardClass", circle)
+	}
+	slots := standard.Slots()
+	if len(slots) != 2 || slots[0].Name != NewSymbol("name") || slots[1].Name != NewSymbol("r") {
+		t.Fatalf("invalid slots: %v", slots)
+	}
+	if len(slots[1].Accessors) != 1 || slots[1].Accessors[0] != NewSymbol("radius") {
+		t.Fatalf("invalid accessors: %v", slots[1].Accessors)
+	}
+	instance, err := w.Interpret(todo, `(create (class <circle>) 'name "c")`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	obj, ok := instance.(StandardObject)
+	if !ok {
+		t.Fatalf("%v is not a StandardObject", instance)
+	}
+	if _, ok := obj.SlotValue(NewSymbol("r")); ok {
+		t.Fatal("r is bound before SetSlotValue")
+	}
+	if err := obj.SetSlotValue(NewSymbol("r"), Integer(2)); err != nil {
+		t.Fatal(err.Error())
+	}
+	if value, ok := obj.SlotValue(NewSymbol("r")); !ok || !value.Equals(Integer(2), STRICT) {
+		t.Fatalf("expected 2, but %v", value)
+	}
+	if err := obj.SetSlotValue(NewSymbol("x"), Integer(2)); err == nil {
+		t.Fatal("SetSlotValue did not fail for the undefined slot")
+	}
+	for _, g := range w.GenericFunctions() {
+		if g.Name() != NewSymbol("area") {
+			continue
+		}
+		methods := g.Methods()
+		if len(methods) != 1 {
+			t.Fatalf("area has %d methods", len(methods))
+		}
+		spec := methods[0].Specializers()
+		if len(spec) != 1 || !spec[0].Equals(circle, STRICT) {
+			t.Fatalf("invalid specializers: %v", spec)
+		}
+		return
+	}
+	t.Fatal("GenericFunctions() does not contain area")
+}
+
+func TestPrintObject(t *testing.T) {
+	w := New()
+	obj, err := w.Interpret(context.TODO(), `
+		(defclass <id> () ((value :initarg value :reader id-value)))
+		(defmethod print-object ((i <id>) stream)
+			(format stream "#~A" (id-value i)))
+		(create (class <id>) 'value 42)`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var buffer strings.Builder
+	if _, err := w.Print(context.TODO(), &buffer, obj, PRINC); err != nil {
+		t.Fatal(err.Error())
+	}
+	if s := buffer.String(); s != "#42" {
+		t.Fatalf("Print wrote %#v", s)
+	}
+	// String calls print-object in the World where the class is defined.
+	if s := obj.String(); s != "#42" {
+		t.Fatalf("String() returned %#v", s)
+	}
+	if s := New().Assert(`(progn (defclass <id> () ((v :initarg v))) (format nil "~A" (create (class <id>) 'v 1)))`, String("<id>{v:1}")); s != "" {
+		t.Fatal(s)
+	}
+}
+
+func TestPrintObjectContext(t *testing.T) {
+	w := New()
+	_, err := w.Interpret(context.TODO(), `
+		(defclass <forever> () ())
+		(defmethod print-object ((f <forever>) stream)
+			(while t (format stream "")))`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = w.Interpret(ctx, `(format nil "~A" (create (class <forever>)))`)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("print-object was not canceled: %v", err)
+	}
+}
@@ -148,7 +148,8 @@ func interactive(lisp *gmnlisp.World) error {
 			continue
 		}
 		if gmnlisp.IsSome(result) {
-			fmt.Fprintln(os.Stdout, result.String())
+			lisp.Print(ctx, os.Stdout, result, gmnlisp.PRINC)
+			fmt.Fprintln(os.Stdout)
 		}
 		fmt.Println()
 	}
@@ -184,14 +185,18 @@ func mains(args []string) error {
 		if err != nil {
 			return fmt.Errorf("%s: %w", args[0], err)
 		}
+		var script []byte
 		if magicByte[0] == '#' || magicByte[0] == '@' {
 			br.ReadString('\n')
+			// keep the line numbers of the rest
+			script = []byte{'\n'}
 		}
-		script, err := io.ReadAll(br)
+		rest, err := io.ReadAll(br)
 		if err != nil {
 			return fmt.Errorf("%s: %w", args[0], err)
 		}
-		_, err = lisp.InterpretBytes(ctx, script)
+		script = append(script, rest...)
+		_, err = lisp.InterpretFile(ctx, args[0], script)
 		return err
 	} else {
 		return interactive(lisp)
@@ -3,7 +3,6 @@ package gmnlisp
 import (
 	"context"
 	"errors"
-	"os"
 	"strconv"
 	"strings"
 )
@@ -99,11 +98,14 @@ func funLoad(ctx context.Context, w *World, arg Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	script, err := os.ReadFile(fname.String())
+	if err := w.RequireCapability(ctx, CapLoad, "load"); err != nil {
+		return nil, err
+	}
+	script, err := w.readFile(fname.String())
 	if err != nil {
 		return nil, err
 	}
-	return w.InterpretBytes(ctx, script)
+	return w.InterpretFile(ctx, fname.String(), script)
 }
 
 func funNotEqual(_ context.Context, _ *World, argv []Node) (Node, error) {
@@ -122,6 +124,15 @@ func xxxxP(arg Node, f1 func(Integer) bool, f2 func(Float) bool) (Node, error) {
 		if f2(value) {
 			return True, nil
 		}
+	} else if value, ok := arg.(BigInt); ok {
+		// BigInt is compared with zero only, so that its sign is enough.
+		if f1(Integer(value.Sign())) {
+			return True, nil
+		}
+	} else if value, ok := arg.(Ratio); ok {
+		if f1(Integer(value.Sign())) {
+			return True, nil
+		}
 	}
 	return Null, nil
 }
@@ -151,7 +162,10 @@ func funMinusp(_ context.Context, _ *World, arg Node) (Node, error) {
 }
 
 func funOddp(_ context.Context, _ *World, arg Node) (Node, error) {
-	if value, ok := arg.(Integer); ok && value%2 == 1 {
+	if value, ok := arg.(Integer); ok && value%2 != 0 {
+		return True, nil
+	}
+	if value, ok := arg.(BigInt); ok && value.Bit(0) == 1 {
 		return True, nil
 	}
 	return Null, nil
@@ -161,6 +175,9 @@ func funEvenp(_ context.Context, _ *World, arg Node) (Node, error) {
 	if value, ok := arg.(Integer); ok && value%2 == 0 {
 		return True, nil
 	}
+	if value, ok := arg.(BigInt); ok && value.Bit(0) == 0 {
+		return True, nil
+	}
 	return Null, nil
 }
 
@@ -171,6 +188,13 @@ func funNullp(_ context.Context, _ *World, arg Node) (Node, error) {
 	return Null, nil
 }
 
+func funIntegerp(_ context.Context, _ *World, arg Node) (Node, error) {
+	if integerClass.InstanceP(arg) {
+		return True, nil
+	}
+	return Null, nil
+}
+
 func funAnyTypep[T Node](_ context.Context, _ *World, arg Node) (Node, error) {
 	if _, ok := arg.(T); ok {
 		return True, nil
@@ -0,0 +1,761 @@
+package gmnlisp
+
+import (
+	"context"
+	"reflect"
+)
+
+// _Compiled is a form translated into a Go closure. The body of a lambda
+// is compiled at its first call, so that the cons tree is not walked and
+// the special forms and the macros are not looked up on each call.
+type _Compiled func(context.Context, *World) (Node, error)
+
+type compiler struct {
+	// w is the world where the lambda is defined. The special forms and
+	// the macros are looked up in it.
+	w *World
+	// scope is the lexical variables of the frames enclosing the form
+	// being compiled.
+	scope *lexicalScope
+}
+
+// lexicalScope is the names of a _Frame at the compile time. The depth of
+// a scope in the chain is the number of the worlds between the frame and
+// the code at runtime.
+type lexicalScope struct {
+	names  []Symbol
+	parent *lexicalScope
+}
+
+// resolve returns the depth and the index of the frame where name is
+// bound. ok is false when name is not a lexical variable of the lambda.
+func (c *compiler) resolve(name Symbol) (depth, index int, ok bool) {
+	return c.scope.resolve(name)
+}
+
+func (scope *lexicalScope) resolve(name Symbol) (depth, index int, ok bool) {
+	for s := scope; s != nil; s = s.parent {
+		for i := len(s.names) - 1; i >= 0; i-- {
+			if s.names[i] == name {
+				return depth, i, true
+			}
+		}
+		depth++
+	}
+	return 0, 0, false
+}
+
+func (c *compiler) push(names []Symbol) {
+	c.scope = &lexicalScope{names: names, parent: c.scope}
+}
+
+func (c *compiler) pop() {
+	c.scope = c.scope.parent
+}
+
+type specialCompiler func(c *compiler, ctx context.Context, args Node, tail bool) (_Compiled, bool)
+
+// specialCompilers are the special forms which are compiled instead of
+// being called through the interpreter. They return false when the form
+// is malformed, so that the interpreter reports the error at runtime.
+var specialCompilers map[uintptr]specialCompiler
+
+func init() {
+	specialCompilers = map[uintptr]specialCompiler{
+		specialKey(cmdQuote):   (*compiler).compileQuote,
+		specialKey(cmdIf):      (*compiler).compileIf,
+		specialKey(cmdProgn):   (*compiler).compileProgn,
+		specialKey(cmdLet):     (*compiler).compileLet,
+		specialKey(cmdLetX):    (*compiler).compileLetX,
+		specialKey(cmdSetq):    (*compiler).compileSetq,
+		specialKey(cmdCond):    (*compiler).compileCond,
+		specialKey(cmdCase):    (*compiler).compileCase,
+		specialKey(cmdAnd):     (*compiler).compileAnd,
+		specialKey(cmdOr):      (*compiler).compileOr,
+		specialKey(cmdWhile):   (*compiler).compileWhile,
+		specialKey(cmdFunCall): (*compiler).compileFunCall,
+		specialKey(cmdBlock):   (*compiler).compileBlock,
+	}
+}
+
+func specialKey(f SpecialF) uintptr {
+	return reflect.ValueOf(f).Pointer()
+}
+
+// compileBody compiles the body of L. The calls of the lambdas at the tail
+// position return _ErrTailRecOpt to the loop of (*_Lambda) apply.
+func compileBody(ctx context.Context, L *_Lambda) _Compiled {
+	c := &compiler{w: L.lexical}
+	c.push(L.names)
+	if body, ok := c.progn(ctx, L.code, true); ok {
+		return body
+	}
+	code := L.code
+	return func(ctx context.Context, w *World) (Node, error) {
+		return Progn(ctx, w, code)
+	}
+}
+
+func constant(value Node) _Compiled {
+	return func(context.Context, *World) (Node, error) {
+		return value, nil
+	}
+}
+
+// interpret makes the closure which evaluates node by the interpreter.
+func interpret(node Node) _Compiled {
+	return func(ctx context.Context, w *World) (Node, error) {
+		return w.Eval(ctx, node)
+	}
+}
+
+// list returns the elements of the proper list.
+func properList(list Node) ([]Node, bool) {
+	var elements []Node
+	for IsSome(list) {
+		cons, ok := list.(*Cons)
+		if !ok {
+			return nil, false
+		}
+		elements = append(elements, cons.getCar())
+		list = cons.Cdr
+	}
+	return elements, true
+}
+
+func (c *compiler) compile(ctx context.Context, node Node, tail bool) _Compiled {
+	switch v := node.(type) {
+	case _Symbol:
+		depth, index, ok := c.resolve(v)
+		if !ok {
+			return func(_ context.Context, w *World) (Node, error) {
+				return w.Get(v)
+			}
+		}
+		if depth == 0 {
+			return func(_ context.Context, w *World) (Node, error) {
+				return w.vars.(*_Frame).values[index], nil
+			}
+		}
+		return func(_ context.Context, w *World) (Node, error) {
+			return frameOf(w, depth).values[index], nil
+		}
+	case *Cons:
+		return c.compileForm(ctx, v, tail)
+	case interface {
+		Eval(context.Context, *World) (Node, error)
+	}:
+		return interpret(node)
+	}
+	return constant(node)
+}
+
+func (c *compiler) progn(ctx context.Context, list Node, tail bool) (_Compiled, bool) {
+	forms, ok := properList(list)
+	if !ok {
+		return nil, false
+	}
+	switch len(forms) {
+	case 0:
+		return constant(Null), true
+	case 1:
+		return c.compile(ctx, forms[0], tail), true
+	}
+	body := make([]_Compiled, len(forms))
+	for i, form := range forms {
+		body[i] = c.compile(ctx, form, tail && i == len(forms)-1)
+	}
+	last := len(body) - 1
+	return func(ctx context.Context, w *World) (Node, error) {
+		for _, f := range body[:last] {
+			if _, err := f(ctx, w); err != nil {
+				return nil, err
+			}
+		}
+		return body[last](ctx, w)
+	}, true
+}
+
+func (c *compiler) compileForm(ctx context.Context, cons *Cons, tail bool) _Compiled {
+	symbol, ok := cons.Car.(Symbol)
+	if !ok {
+		return interpret(cons)
+	}
+	if f, err := c.w.GetFunc(symbol); err == nil {
+		if sf, ok := f.(SpecialF); ok {
+			if sc, ok := specialCompilers[specialKey(sf)]; ok {
+				if code, ok := sc(c, ctx, cons.Cdr, tail); ok {
+					return site(cons, symbol, code)
+				}
+			}
+			return interpret(cons)
+		}
+		if m := macroOf(ctx, c.w, f); m != nil {
+			expanded, err := m.expand(ctx, c.w, cons.Cdr)
+			if err != nil {
+				return interpret(cons)
+			}
+			return redefinable(symbol, f, c.compile(ctx, expanded, tail), interpret(cons))
+		}
+	}
+	return c.compileCall(ctx, cons, symbol, tail)
+}
+
+// redefinable makes the closure which calls code while symbol is the macro
+// f and calls the interpreter after the macro is redefined.
+func redefinable(symbol Symbol, f Callable, code, interpreter _Compiled) _Compiled {
+	return func(ctx context.Context, w *World) (Node, error) {
+		if g, err := w.GetFunc(symbol); err == nil && g == f {
+			return code(ctx, w)
+		}
+		return interpreter(ctx, w)
+	}
+}
+
+// site makes the closure which does what Cons.Eval does around the call:
+// counting the limits, setting the call site and attaching the position to
+// the error.
+func site(cons *Cons, symbol Symbol, code _Compiled) _Compiled {
+	pos := cons.pos
+	return func(ctx context.Context, w *World) (Node, error) {
+		limited := w.limited
+		if limited {
+			if err := w.enterEval(ctx); err != nil {
+				return nil, err
+			}
+		}
+		save := w.callSite
+		if pos != nil {
+			w.callSite = pos
+		}
+		rc, err := code(ctx, w)
+		w.callSite = save
+		if limited {
+			w.leaveEval()
+		}
+		if err != nil {
+			if _, ok := err.(*_ErrTailRecOpt); ok {
+				return nil, err
+			}
+			return nil, callError(err, symbol, pos)
+		}
+		return rc, nil
+	}
+}
+
+func evalAll(ctx context.Context, w *World, args []_Compiled) ([]Node, error) {
+	values := make([]Node, len(args))
+	for i, arg := range args {
+		value, err := arg(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func (c *compiler) compileCall(ctx context.Context, cons *Cons, symbol Symbol, tail bool) _Compiled {
+	forms, ok := properList(cons.Cdr)
+	if !ok {
+		return interpret(cons)
+	}
+	args := make([]_Compiled, len(forms))
+	for i, form := range forms {
+		args[i] = c.compile(ctx, form, false)
+	}
+	return site(cons, symbol, func(ctx context.Context, w *World) (Node, error) {
+		f, err := w.GetFunc(symbol)
+		if err != nil {
+			return nil, err
+		}
+		return callCompiled(ctx, w, f, cons.Cdr, args, tail)
+	})
+}
+
+// callCompiled calls f with the arguments compiled. When f does not
+// evaluate its arguments such as the macros, it is given the source of
+// them instead. When tail is true and f is a lambda not traced, it
+// returns _ErrTailRecOpt instead of calling f.
+func callCompiled(ctx context.Context, w *World, f Callable, source Node, args []_Compiled, tail bool) (Node, error) {
+	callee, err := resolveCallable(ctx, w, f)
+	if err != nil {
+		return nil, err
+	}
+	switch fn := callee.(type) {
+	case Function0:
+		if len(args) == 0 {
+			return fn(ctx, w)
+		}
+	case Function1:
+		if len(args) == 1 {
+			value, err := args[0](ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			return fn(ctx, w, value)
+		}
+	case Function2:
+		if len(args) == 2 {
+			first, err := args[0](ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			second, err := args[1](ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			return fn(ctx, w, first, second)
+		}
+	case *Function:
+		if min, max := fn.bounds(); min <= len(args) && len(args) <= max {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+			values, err := evalAll(ctx, w, args)
+			if err != nil {
+				return nil, err
+			}
+			return fn.F(ctx, w, values)
+		}
+	case *_Lambda:
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+		values, err := evalAll(ctx, w, args)
+		if err != nil {
+			return nil, err
+		}
+		if tail && !traced(w, fn) {
+			return nil, &_ErrTailRecOpt{callee: fn, args: values}
+		}
+		return fn.apply(ctx, w, values)
+	case *_Generic:
+	default:
+		return f.Call(ctx, w, source)
+	}
+	values, err := evalAll(ctx, w, args)
+	if err != nil {
+		return nil, err
+	}
+	return callee.Call(ctx, w, UnevalList(values...))
+}
+
+// blockName returns the name of the block, which is nil when omitted.
+func blockName(node Node) (Symbol, bool) {
+	if IsNone(node) {
+		return nulSymbol, true
+	}
+	symbol, ok := node.(Symbol)
+	return symbol, ok
+}
+
+func (c *compiler) compileBlock(ctx context.Context, args Node, _ bool) (_Compiled, bool) {
+	cons, ok := args.(*Cons)
+	if !ok {
+		return nil, false
+	}
+	name, ok := blockName(cons.Car)
+	if !ok {
+		return nil, false
+	}
+	body, ok := c.progn(ctx, cons.Cdr, false)
+	if !ok {
+		return nil, false
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		return inBlock(ctx, w, name, body)
+	}, true
+}
+
+func (c *compiler) compileFunCall(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok || len(forms) < 1 {
+		return nil, false
+	}
+	function := c.compile(ctx, forms[0], false)
+	params := make([]_Compiled, len(forms)-1)
+	for i, form := range forms[1:] {
+		params[i] = c.compile(ctx, form, false)
+	}
+	source := args.(*Cons).Cdr
+	return func(ctx context.Context, w *World) (Node, error) {
+		value, err := function(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		f, err := ExpectFunction(ctx, w, value)
+		if err != nil {
+			return nil, err
+		}
+		return callCompiled(ctx, w, f, source, params, tail)
+	}, true
+}
+
+func (c *compiler) compileQuote(_ context.Context, args Node, _ bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok || len(forms) != 1 {
+		return nil, false
+	}
+	return constant(forms[0]), true
+}
+
+func (c *compiler) compileIf(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok || len(forms) < 2 || len(forms) > 3 {
+		return nil, false
+	}
+	test := c.compile(ctx, forms[0], false)
+	then := c.compile(ctx, forms[1], tail)
+	if len(forms) == 2 || IsNone(forms[2]) {
+		return func(ctx context.Context, w *World) (Node, error) {
+			cond, err := test(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if IsSome(cond) {
+				return then(ctx, w)
+			}
+			return Null, nil
+		}, true
+	}
+	otherwise := c.compile(ctx, forms[2], tail)
+	return func(ctx context.Context, w *World) (Node, error) {
+		cond, err := test(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		if IsSome(cond) {
+			return then(ctx, w)
+		}
+		return otherwise(ctx, w)
+	}, true
+}
+
+func (c *compiler) compileProgn(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	return c.progn(ctx, args, tail)
+}
+
+type binding struct {
+	name Symbol
+	init _Compiled
+}
+
+// bindings compiles the first argument of let and let*. For let*, scope
+// is given to add the names one by one after compiling each initform.
+func (c *compiler) bindings(ctx context.Context, list Node, scope *lexicalScope) ([]binding, bool) {
+	items, ok := properList(list)
+	if !ok {
+		return nil, false
+	}
+	result := make([]binding, len(items))
+	for i, item := range items {
+		if symbol, ok := item.(Symbol); ok {
+			result[i] = binding{name: symbol, init: constant(Null)}
+			if scope != nil {
+				scope.names = append(scope.names, symbol)
+			}
+			continue
+		}
+		pair, ok := properList(item)
+		if !ok || len(pair) != 2 {
+			return nil, false
+		}
+		symbol, ok := pair[0].(Symbol)
+		if !ok {
+			return nil, false
+		}
+		result[i] = binding{name: symbol, init: c.compile(ctx, pair[1], false)}
+		if scope != nil {
+			scope.names = append(scope.names, symbol)
+		}
+	}
+	return result, true
+}
+
+func bindingNames(vars []binding) []Symbol {
+	names := make([]Symbol, len(vars))
+	for i, v := range vars {
+		names[i] = v.name
+	}
+	return names
+}
+
+func (c *compiler) compileLet(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	cons, ok := args.(*Cons)
+	if !ok {
+		return nil, false
+	}
+	vars, ok := c.bindings(ctx, cons.Car, nil)
+	if !ok {
+		return nil, false
+	}
+	names := bindingNames(vars)
+	c.push(names)
+	body, ok := c.progn(ctx, cons.Cdr, tail)
+	c.pop()
+	if !ok {
+		return nil, false
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		lexical := newFrame(names)
+		for _, v := range vars {
+			value, err := v.init(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			lexical.values = append(lexical.values, value)
+		}
+		return body(ctx, w.Let(lexical))
+	}, true
+}
+
+func (c *compiler) compileLetX(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	cons, ok := args.(*Cons)
+	if !ok {
+		return nil, false
+	}
+	// each initform sees the variables bound before it.
+	c.push(nil)
+	vars, ok := c.bindings(ctx, cons.Car, c.scope)
+	if !ok {
+		c.pop()
+		return nil, false
+	}
+	names := c.scope.names
+	body, ok := c.progn(ctx, cons.Cdr, tail)
+	c.pop()
+	if !ok {
+		return nil, false
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		lexical := newFrame(names)
+		newWorld := w.Let(lexical)
+		for _, v := range vars {
+			value, err := v.init(ctx, newWorld)
+			if err != nil {
+				return nil, err
+			}
+			lexical.values = append(lexical.values, value)
+		}
+		return body(ctx, newWorld)
+	}, true
+}
+
+func (c *compiler) compileSetq(ctx context.Context, args Node, _ bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok || len(forms)%2 != 0 {
+		return nil, false
+	}
+	type assignment struct {
+		binding
+		depth, index int
+		lexical      bool
+	}
+	vars := make([]assignment, 0, len(forms)/2)
+	for i := 0; i < len(forms); i += 2 {
+		symbol, ok := forms[i].(Symbol)
+		if !ok {
+			return nil, false
+		}
+		depth, index, lexical := c.resolve(symbol)
+		vars = append(vars, assignment{
+			binding: binding{name: symbol, init: c.compile(ctx, forms[i+1], false)},
+			depth:   depth,
+			index:   index,
+			lexical: lexical,
+		})
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		var value Node = Null
+		for _, v := range vars {
+			var err error
+			value, err = v.init(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if v.lexical {
+				frameOf(w, v.depth).values[v.index] = value
+			} else if err := w.Set(v.name, value); err != nil {
+				return value, err
+			}
+		}
+		return value, nil
+	}, true
+}
+
+type clause struct {
+	test _Compiled
+	body _Compiled
+}
+
+func (c *compiler) compileCond(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	items, ok := properList(args)
+	if !ok {
+		return nil, false
+	}
+	clauses := make([]clause, len(items))
+	for i, item := range items {
+		cons, ok := item.(*Cons)
+		if !ok {
+			return nil, false
+		}
+		body, ok := c.progn(ctx, cons.Cdr, tail)
+		if !ok {
+			return nil, false
+		}
+		clauses[i] = clause{test: c.compile(ctx, cons.getCar(), false), body: body}
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		for _, cl := range clauses {
+			cond, err := cl.test(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if IsSome(cond) {
+				return cl.body(ctx, w)
+			}
+		}
+		return Null, nil
+	}, true
+}
+
+func (c *compiler) compileCase(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	items, ok := properList(args)
+	if !ok || len(items) < 1 {
+		return nil, false
+	}
+	key := c.compile(ctx, items[0], false)
+	type caseClause struct {
+		keys      []Node
+		otherwise bool
+		body      _Compiled
+	}
+	var clauses []caseClause
+	for _, item := range items[1:] {
+		cons, ok := item.(*Cons)
+		if !ok {
+			return nil, false
+		}
+		body, ok := c.progn(ctx, cons.Cdr, tail)
+		if !ok {
+			return nil, false
+		}
+		cl := caseClause{body: body}
+		if _, ok := cons.getCar().(*Cons); ok {
+			if cl.keys, ok = properList(cons.Car); !ok {
+				return nil, false
+			}
+		} else if cons.getCar().Equals(True, STRICT) {
+			cl.otherwise = true
+		} else {
+			continue
+		}
+		clauses = append(clauses, cl)
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		value, err := key(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		for _, cl := range clauses {
+			if cl.otherwise {
+				return cl.body(ctx, w)
+			}
+			for _, k := range cl.keys {
+				if value.Equals(k, EQUALP) {
+					return cl.body(ctx, w)
+				}
+			}
+		}
+		return Null, nil
+	}, true
+}
+
+func (c *compiler) compileAnd(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok {
+		return nil, false
+	}
+	if len(forms) == 0 {
+		return constant(True), true
+	}
+	body := make([]_Compiled, len(forms))
+	for i, form := range forms {
+		body[i] = c.compile(ctx, form, tail && i == len(forms)-1)
+	}
+	last := len(body) - 1
+	return func(ctx context.Context, w *World) (Node, error) {
+		for _, f := range body[:last] {
+			value, err := f(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if IsNone(value) {
+				return Null, nil
+			}
+		}
+		value, err := body[last](ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		if IsNone(value) {
+			return Null, nil
+		}
+		return value, nil
+	}, true
+}
+
+func (c *compiler) compileOr(ctx context.Context, args Node, tail bool) (_Compiled, bool) {
+	forms, ok := properList(args)
+	if !ok || len(forms) == 0 {
+		return nil, false
+	}
+	body := make([]_Compiled, len(forms))
+	for i, form := range forms {
+		body[i] = c.compile(ctx, form, tail && i == len(forms)-1)
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		for _, f := range body {
+			value, err := f(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if IsSome(value) {
+				return value, nil
+			}
+		}
+		return Null, nil
+	}, true
+}
+
+func (c *compiler) compileWhile(ctx context.Context, args Node, _ bool) (_Compiled, bool) {
+	cons, ok := args.(*Cons)
+	if !ok {
+		return nil, false
+	}
+	test := c.compile(ctx, cons.getCar(), false)
+	body, ok := c.progn(ctx, cons.Cdr, false)
+	if !ok {
+		return nil, false
+	}
+	return func(ctx context.Context, w *World) (Node, error) {
+		var last Node = Null
+		for {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+			cont, err := test(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+			if IsNone(cont) {
+				return last, nil
+			}
+			last, err = body(ctx, w)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}, true
+}
@@ -0,0 +1,21 @@
+package gmnlisp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestTailCall(t *testing.T) {
+	w := New()
+	w.SetLimits(Limits{Depth: 200})
+	value, err := w.Interpret(context.TODO(), `
+		(defun state-a (n) (if (= n 0) 'a (state-b (- n 1))))
+		(defun state-b (n) (if (= n 0) 'b (funcall #'state-a (- n 1))))
+		(state-a 10001)`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if value != NewSymbol("b") {
+		t.Fatalf("got %v", value)
+	}
+}
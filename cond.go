@@ -7,11 +7,12 @@ import (
 )
 
 func IsNonLocalExists(err error) bool {
-	var e1 *_ErrEarlyReturns // block & return-from
-	var e2 *_ErrThrown       // catch & throw
-	var e3 *_ErrTagBody      // tagbody & go
+	var e1 *_ErrEarlyReturns  // block & return-from
+	var e2 *_ErrThrown        // catch & throw
+	var e3 *_ErrTagBody       // tagbody & go
+	var e4 *_ErrInvokeRestart // restart-case & invoke-restart
 
-	return errors.As(err, &e1) || errors.As(err, &e2) || errors.As(err, &e3)
+	return errors.As(err, &e1) || errors.As(err, &e2) || errors.As(err, &e3) || errors.As(err, &e4)
 }
 
 func cmdWithHandler(ctx context.Context, w *World, node Node) (Node, error) {
@@ -23,40 +24,33 @@ func cmdWithHandler(ctx context.Context, w *World, node Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	w.handler = append(w.handler, handler)
+	save := w.handler
+	defer func() { w.handler = save }()
+	w.handler = append(save[:len(save):len(save)], handler)
 
 	value, err := Progn(ctx, w, node)
 
-	if L := len(w.handler); L > 0 {
-		w.handler = w.handler[:L-1]
-	}
-
-	// 本来、場合によっては継続処理があるため、ハンドラー関数はエラーが起きた場所で
-	// 呼ばなければいけないが、 古いコードは error を返して、error の処理元で
-	// ハンドラー関数を呼ぶことを想定し、エラー発生箇所で呼べていない
-	//
-	// エラー発生箇所でハンドラー関数が呼ばれて、ここに戻る時は大域脱出系エラーで
-	// 戻ってくるはずなので、そういった場合は、ここでハンドラー関数を呼ばない
-	// ようにしている
-	// (将来的にはエラーが起きた場所で呼ぶよう変更しなくてはいけない)
-
-	if err == nil || IsNonLocalExists(err) || errors.Is(err, errHandlerReturnNormally) {
+	// The conditions signaled by signal-condition or raiseXXXX have been
+	// passed to the handlers where they occurred. The other errors are
+	// signaled here when they reach the innermost with-handler.
+	var signaled *_ErrSignaled
+	if err == nil || IsNonLocalExists(err) || errors.As(err, &signaled) {
 		return value, err
 	}
 	var errorValue interface {
 		Node
 		Error() string
 	}
-	var err2 error
+	var condition Node
 	if errors.As(err, &errorValue) {
-		_, err2 = handler.Call(ctx, w, &Cons{Car: errorValue, Cdr: Null})
+		condition = errorValue
 	} else {
-		_, err2 = handler.Call(ctx, w, &Cons{Car: ErrorNode{Value: err}, Cdr: Null})
-	}
-	if err2 != nil {
-		return nil, err2
+		condition = ErrorNode{Value: err}
 	}
-	return raiseControlError(ctx, w, errors.New("Handler return normally"))
+	return nil, w.withBacktraceOf(err, func() error {
+		_, e := signal(ctx, w, condition, false)
+		return e
+	})
 }
 
 type _ErrContinueCondition struct {
@@ -69,31 +63,31 @@ func (e *_ErrContinueCondition) Error() string {
 }
 
 var symReportCondition = NewSymbol("report-condition")
-var reportCondition = &_Generic{
-	Symbol:  symReportCondition,
-	argc:    2,
-	methods: []*_Method{},
+
+func newReportCondition() *_Generic {
+	return &_Generic{
+		Symbol:  symReportCondition,
+		argc:    2,
+		methods: []*_Method{},
+	}
 }
 
 func funSignalCondition(ctx context.Context, w *World, cond, continueable Node) (Node, error) {
 	if len(w.handler) <= 0 {
 		buffer := &StringBuilder{}
-		if _, err := reportCondition.Call(ctx, w, UnevalList(cond, buffer)); err == nil {
-			return nil, errors.New(buffer.String())
-		} else if !errors.Is(err, ErrNoMatchMethods) {
-			return nil, fmt.Errorf("%w in (report-condition)", err)
+		if reportCondition, ok := w.defun.Get(symReportCondition); ok {
+			if _, err := reportCondition.Call(ctx, w, UnevalList(cond, buffer)); err == nil {
+				return nil, errors.New(buffer.String())
+			} else if !errors.Is(err, ErrNoMatchMethods) {
+				return nil, fmt.Errorf("%w in (report-condition)", err)
+			}
 		}
 		if err, ok := cond.(error); ok {
 			return nil, err
 		}
 		return nil, errors.New(cond.String())
 	}
-	rv, err := w.handler[len(w.handler)-1].Call(ctx, w, &Cons{Car: cond})
-	var e *_ErrContinueCondition
-	if IsSome(continueable) && errors.As(err, &e) {
-		return e.Value, nil
-	}
-	return rv, err
+	return signal(ctx, w, cond, IsSome(continueable))
 }
 
 func cmdContinueCondition(ctx context.Context, w *World, node Node) (Node, error) {
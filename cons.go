@@ -2,6 +2,7 @@ package gmnlisp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -10,6 +11,16 @@ import (
 type Cons struct {
 	Car Node
 	Cdr Node
+	pos *Position
+}
+
+// Position returns where the cons was read by the parser.
+// ok is false for the conses made at runtime.
+func (cons *Cons) Position() (pos Position, ok bool) {
+	if cons.pos == nil {
+		return Position{}, false
+	}
+	return *cons.pos, true
 }
 
 var consClass = registerNewAbstractClass[*Cons]("<cons>")
@@ -181,32 +192,67 @@ func (cons *Cons) Equals(n Node, m EqlMode) bool {
 }
 
 func (cons *Cons) Eval(ctx context.Context, w *World) (Node, error) {
+	if w.limited {
+		if err := w.enterEval(ctx); err != nil {
+			return nil, err
+		}
+		defer w.leaveEval()
+	}
 	var rc Node
 	var err error
+	save := w.callSite
+	if cons.pos != nil {
+		w.callSite = cons.pos
+	}
 	symbol, ok := cons.Car.(Symbol)
 	if !ok {
-		f, _err := w.Eval(ctx, cons.Car)
-		if _err != nil {
-			return nil, _err
-		}
-		function, ok := f.(FunctionRef)
-		if !ok {
-			rc, err = callHandler[FunctionRef](ctx, w, true, &_UndefinedEntity{
-				name:  NewSymbol(f.String()),
-				space: symFunction,
-			})
-		} else {
-			rc, err = function.value.Call(ctx, w, cons.Cdr)
+		var f Node
+		f, err = w.Eval(ctx, cons.Car)
+		if err == nil {
+			function, ok := f.(FunctionRef)
+			if !ok {
+				rc, err = callHandler[FunctionRef](ctx, w, true, &_UndefinedEntity{
+					name:  NewSymbol(f.String()),
+					space: symFunction,
+				})
+			} else {
+				rc, err = function.value.Call(ctx, w, cons.Cdr)
+			}
 		}
 	} else {
-		function, _err := w.GetFunc(symbol)
-		if _err != nil {
-			return nil, _err
+		var function Callable
+		function, err = w.GetFunc(symbol)
+		if err == nil {
+			rc, err = function.Call(ctx, w, cons.Cdr)
 		}
-		rc, err = function.Call(ctx, w, cons.Cdr)
 	}
+	w.callSite = save
 	if err != nil {
-		return nil, fmt.Errorf("%w\n\tat %v", err, symbol)
+		return nil, callError(err, symbol, cons.pos)
 	}
 	return rc, nil
 }
+
+// callError attaches the position and the name of the function called at
+// pos to err.
+func callError(err error, symbol Symbol, pos *Position) error {
+	if pos != nil {
+		var posErr *PositionError
+		if !errors.As(err, &posErr) {
+			err = &PositionError{Err: err, Pos: *pos}
+			// The message already starts with the position.
+			pos = nil
+		} else if posErr.Pos == *pos {
+			pos = nil
+		}
+	}
+	// After the backtrace is attached, it tells the callers instead.
+	var bt *Backtrace
+	if errors.As(err, &bt) {
+		return err
+	}
+	if pos != nil {
+		return fmt.Errorf("%w\n\tat %v (%s)", err, symbol, pos)
+	}
+	return fmt.Errorf("%w\n\tat %v", err, symbol)
+}
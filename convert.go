@@ -3,6 +3,7 @@ package gmnlisp
 import (
 	"context"
 	"fmt"
+	"math/big"
 	"strconv"
 	"unicode"
 )
@@ -40,6 +41,9 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		case integerClass.name:
 			i, err := strconv.ParseInt(val.String(), 10, 64)
 			if err != nil {
+				if b, ok := new(big.Int).SetString(val.String(), 10); ok {
+					return integerOf(b), nil
+				}
 				return callHandler[*DomainError](ctx, w, true, &DomainError{
 					Object:        val,
 					ExpectedClass: integerClass,
@@ -62,13 +66,17 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		case classList:
 			var buffer ListBuilder
 			for _, r := range val {
-				buffer.Add(ctx, w, Rune(r))
+				if err := buffer.Add(ctx, w, Rune(r)); err != nil {
+					return nil, err
+				}
 			}
 			return buffer.Sequence(), nil
 		case classVector:
 			var buffer VectorBuilder
 			for _, r := range val {
-				buffer.Add(ctx, w, Rune(r))
+				if err := buffer.Add(ctx, w, Rune(r)); err != nil {
+					return nil, err
+				}
 			}
 			return buffer.Sequence(), nil
 		}
@@ -77,7 +85,7 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		case floatClass.name:
 			return val, nil
 		case stringClass.name:
-			return String(strconv.FormatFloat(float64(val), 'f', -1, 64)), nil
+			return w.newString(ctx, strconv.FormatFloat(float64(val), 'f', -1, 64))
 		}
 	case Integer:
 		switch class {
@@ -90,7 +98,25 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		case floatClass.name:
 			return Float(val), nil
 		case stringClass.name:
-			return String(fmt.Sprintf("%d", int(val))), nil
+			return w.newString(ctx, fmt.Sprintf("%d", int(val)))
+		}
+	case Ratio:
+		switch class {
+		case ratioClass.name:
+			return val, nil
+		case floatClass.name:
+			return val.Float(), nil
+		case stringClass.name:
+			return w.newString(ctx, val.String())
+		}
+	case BigInt:
+		switch class {
+		case integerClass.name:
+			return val, nil
+		case floatClass.name:
+			return val.Float(), nil
+		case stringClass.name:
+			return w.newString(ctx, val.String())
 		}
 	case *Cons:
 		switch class {
@@ -99,7 +125,9 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		case classVector:
 			var buffer VectorBuilder
 			for IsSome(val) {
-				buffer.Add(ctx, w, val.Car)
+				if err := buffer.Add(ctx, w, val.Car); err != nil {
+					return nil, err
+				}
 				var ok bool
 				val, ok = val.Cdr.(*Cons)
 				if !ok {
@@ -114,6 +142,9 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 		}
 		switch class {
 		case classList:
+			if err := w.useConses(ctx, int64(len(val.list))); err != nil {
+				return nil, err
+			}
 			var cons Node = nil
 			for i := len(val.list) - 1; i >= 0; i-- {
 				cons = &Cons{
@@ -126,7 +157,7 @@ func cmdConvert(ctx context.Context, w *World, list Node) (Node, error) {
 	case Symbol:
 		switch class {
 		case stringClass.name:
-			return String(val.String()), nil
+			return w.newString(ctx, val.String())
 		case symbolClass.name:
 			return val, nil
 		}
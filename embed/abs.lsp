@@ -1,4 +0,0 @@
-(lambda (x)
-  (if (< x 0)
-    (- x)
-    x))
@@ -68,7 +68,11 @@ type ParseError struct {
 	ExpectedClass Class
 }
 
-type StorageExhausted struct{}
+// StorageExhausted is raised when the storage or one of the Limits is
+// exhausted. Resource is the name of the exceeded limit.
+type StorageExhausted struct {
+	Resource string
+}
 
 type StreamError struct {
 	Stream Node
@@ -178,36 +182,72 @@ type errorAndNode interface {
 	Node
 }
 
-type callHandlerLimitter struct{}
-
 var errHandlerReturnNormally = errors.New("Handler return normally")
 
-func callHandler[T Node](ctx context.Context, w *World, cont bool, condition errorAndNode) (T, error) {
-	var zero T
-	if len(w.handler) > 0 {
-		if v := ctx.Value(callHandlerLimitter{}); v != nil {
-			return zero, condition
+var errNotContinuable = errors.New("condition is not continuable")
+
+// _ErrSignaled is the error which has already been passed to the handlers.
+// with-handler does not call its handler with it again.
+type _ErrSignaled struct {
+	err error
+}
+
+func (e *_ErrSignaled) Error() string {
+	return e.err.Error()
+}
+
+func (e *_ErrSignaled) Unwrap() error {
+	return e.err
+}
+
+// signal calls the handlers from the innermost one at the place where
+// condition is signaled. While a handler is called, only the handlers
+// established outside of it are active. When a handler calls
+// continue-condition for the continuable condition, its value is returned.
+// w.handler must not be empty.
+func signal(ctx context.Context, w *World, condition Node, cont bool) (Node, error) {
+	save := w.handler
+	defer func() { w.handler = save }()
+	for i := len(save) - 1; i >= 0; i-- {
+		w.handler = save[:i]
+		_, err := save[i].Call(ctx, w, UnevalList(condition))
+		if err == nil {
+			condition = ControlError{err: errHandlerReturnNormally}
+			cont = false
+			continue
 		}
-		ctx = context.WithValue(ctx, callHandlerLimitter{}, 1)
-		for h := w.handler; len(h) > 0; h = h[:len(h)-1] {
-			_, e := h[len(h)-1].Call(ctx, w, UnevalList(condition))
-			var ce *_ErrContinueCondition
-			if cont && errors.As(e, &ce) {
-				if v, ok := ce.Value.(T); ok {
-					return v, nil
-				}
-			}
-			if IsNonLocalExists(e) {
-				return zero, e
+		var ce *_ErrContinueCondition
+		if errors.As(err, &ce) {
+			if cont {
+				return ce.Value, nil
 			}
-			if e != nil {
-				return zero, condition
-			}
-			condition = &ControlError{err: errHandlerReturnNormally}
+			_, err = raiseControlError(ctx, w, errNotContinuable)
+		}
+		if IsNonLocalExists(err) {
+			return nil, err
 		}
-		return zero, &ControlError{err: errHandlerReturnNormally}
+		var signaled *_ErrSignaled
+		if errors.As(err, &signaled) {
+			return nil, err
+		}
+		return nil, &_ErrSignaled{err: err}
 	}
-	return zero, condition
+	return nil, &_ErrSignaled{err: ControlError{err: errHandlerReturnNormally}}
+}
+
+func callHandler[T Node](ctx context.Context, w *World, cont bool, condition errorAndNode) (T, error) {
+	var zero T
+	if len(w.handler) <= 0 {
+		return zero, condition
+	}
+	value, err := signal(ctx, w, condition, cont)
+	if err != nil {
+		return zero, err
+	}
+	if v, ok := value.(T); ok {
+		return v, nil
+	}
+	return zero, &_ErrSignaled{err: condition}
 }
 
 func ExpectInterface[T Node](ctx context.Context, w *World, v Node, class Class) (T, error) {
@@ -241,6 +281,9 @@ type _UndefinedEntity struct {
 }
 
 func (u *_UndefinedEntity) Error() string {
+	if u.space == symSlot {
+		return fmt.Sprintf("unbound slot: %#v", u.name.String())
+	}
 	return fmt.Sprintf("undefined %s: %#v", u.space.String(), u.name.String())
 }
 
@@ -272,6 +315,9 @@ func (u *_UndefinedEntity) ClassOf() Class {
 	if u.space.Equals(symDynamicVariable, STRICT) {
 		return unboundVariableClass
 	}
+	if u.space.Equals(symClass, STRICT) || u.space.Equals(symSlot, STRICT) {
+		return undefinedEntityClass
+	}
 	return undefinedFunctionClass
 }
 
@@ -338,11 +384,14 @@ func (s StorageExhausted) Equals(n Node, _ EqlMode) bool {
 }
 
 func (s StorageExhausted) String() string {
+	if s.Resource != "" {
+		return "storage exhausted: " + s.Resource
+	}
 	return "storage exhausted"
 }
 
 func (s StorageExhausted) Error() string {
-	return "storage exhausted"
+	return s.String()
 }
 
 func (s StreamError) ClassOf() Class {
@@ -392,3 +441,18 @@ func (e EndOfStream) String() string {
 func (e EndOfStream) Error() string {
 	return "<end-of-stream>"
 }
+
+// PositionError is the error annotated with the position of the innermost
+// form read from the source that was being evaluated when Err occurred.
+type PositionError struct {
+	Err error
+	Pos Position
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Pos.String(), e.Err.Error())
+}
+
+func (e *PositionError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,64 @@
+package gmnlisp
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// SetFS makes load, open-input-file, with-open-input-file, probe-file
+// and file-length resolve the filenames against fsys instead of the
+// filesystem of the host. The filenames are cleaned and the leading
+// slashes are removed because fs.FS accepts only unrooted paths.
+// Because fs.FS is read-only, open-output-file, open-io-file and the
+// macros using them raise SecurityError while fsys is set.
+// SetFS(nil) restores the filesystem of the host.
+func (w *World) SetFS(fsys fs.FS) {
+	w.fsys = fsys
+}
+
+// FS returns the filesystem given by SetFS or nil.
+func (w *World) FS() fs.FS {
+	return w.fsys
+}
+
+func (w *World) fsName(name string) string {
+	return strings.TrimLeft(path.Clean(filepath.ToSlash(name)), "/")
+}
+
+func (w *World) openFile(name string) (fs.File, error) {
+	if w.fsys == nil {
+		return os.Open(name)
+	}
+	return w.fsys.Open(w.fsName(name))
+}
+
+// createFile opens the file of the host to write for operation. It is
+// rejected while the filesystem is given by SetFS.
+func (w *World) createFile(ctx context.Context, name string, flag int, operation string) (*os.File, error) {
+	if w.fsys != nil {
+		_, err := callHandler[Node](ctx, w, false, SecurityError{
+			Capability: CapFileWrite,
+			Operation:  operation,
+		})
+		return nil, err
+	}
+	return os.OpenFile(name, flag, 0644)
+}
+
+func (w *World) readFile(name string) ([]byte, error) {
+	if w.fsys == nil {
+		return os.ReadFile(name)
+	}
+	return fs.ReadFile(w.fsys, w.fsName(name))
+}
+
+func (w *World) statFile(name string) (fs.FileInfo, error) {
+	if w.fsys == nil {
+		return os.Stat(name)
+	}
+	return fs.Stat(w.fsys, w.fsName(name))
+}
@@ -29,6 +29,10 @@ func printInt(w io.Writer, value Node, base int, args ...int) error {
 	var body string
 	if d, ok := value.(Integer); ok {
 		body = strconv.FormatInt(int64(d), base)
+	} else if b, ok := value.(BigInt); ok {
+		body = b.Text(base)
+	} else if r, ok := value.(Ratio); ok {
+		body = r.Num().Text(base) + "/" + r.Denom().Text(base)
 	} else if f, ok := value.(Float); ok {
 		body = strconv.FormatInt(int64(f), base)
 	} else {
@@ -50,9 +54,9 @@ func funFormatObject(ctx context.Context, w *World, list []Node) (Node, error) {
 	return tAndNilToWriter(ctx, w, list, func(writer io.Writer, list []Node) error {
 		var err error
 		if IsNone(list[1]) { // ~a (AS-IS)
-			_, err = tryPrintTo(writer, list[0], PRINC)
+			_, err = printTo(ctx, w, writer, list[0], PRINC)
 		} else { // ~s (S expression)
-			_, err = tryPrintTo(writer, list[0], PRINT)
+			_, err = printTo(ctx, w, writer, list[0], PRINT)
 		}
 		return err
 	})
@@ -119,6 +123,10 @@ func printFloat(w io.Writer, value Node, mark byte, args ...int) error {
 	var body string
 	if d, ok := value.(Integer); ok {
 		body = strconv.FormatFloat(float64(d), mark, prec, 64)
+	} else if b, ok := value.(BigInt); ok {
+		body = strconv.FormatFloat(float64(b.Float()), mark, prec, 64)
+	} else if r, ok := value.(Ratio); ok {
+		body = strconv.FormatFloat(float64(r.Float()), mark, prec, 64)
 	} else if f, ok := value.(Float); ok {
 		body = strconv.FormatFloat(float64(f), mark, prec, 64)
 	} else {
@@ -316,7 +324,7 @@ func formatSub(ctx context.Context, world *World, w io.Writer, argv []Node) erro
 		case 'g', 'G':
 			err = printFloat(w, value, 'g', parameter...)
 		case 'a', 'A':
-			n, err := tryPrintTo(w, value, PRINC)
+			n, err := printTo(ctx, world, w, value, PRINC)
 			if err != nil {
 				return err
 			}
@@ -324,7 +332,7 @@ func formatSub(ctx context.Context, world *World, w io.Writer, argv []Node) erro
 				printSpaces(parameter[0]-n, w)
 			}
 		case 's', 'S':
-			n, err := tryPrintTo(w, value, PRINT)
+			n, err := printTo(ctx, world, w, value, PRINT)
 			if err != nil {
 				return err
 			}
@@ -344,8 +352,13 @@ func formatSub(ctx context.Context, world *World, w io.Writer, argv []Node) erro
 func tAndNilToWriter(ctx context.Context, w *World, argv []Node, f func(io.Writer, []Node) error) (Node, error) {
 	if IsNone(argv[0]) {
 		var buffer StringBuilder
-		err := f(&buffer, argv[1:])
-		return buffer.Sequence(), err
+		if err := f(&buffer, argv[1:]); err != nil {
+			return nil, err
+		}
+		if err := w.useStrings(ctx, int64(buffer.Len())); err != nil {
+			return nil, err
+		}
+		return buffer.Sequence(), nil
 	}
 	if True.Equals(argv[0], STRICT) {
 		return Null, f(w.stdout, argv[1:])
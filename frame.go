@@ -0,0 +1,79 @@
+package gmnlisp
+
+// _Frame is the Scope of the lexical variables made by lambda, let and let*.
+// The compiled code reads and writes values by the indexes resolved at the
+// compile time, and the interpreter finds them by names as other Scopes.
+// Only the first len(values) names are bound, so that let* can bind its
+// variables one by one.
+type _Frame struct {
+	names  []Symbol
+	values []Node
+}
+
+// _SmallFrame is the frame allocated with its values at once.
+type _SmallFrame struct {
+	_Frame
+	buffer [2]Node
+}
+
+func newFrame(names []Symbol) *_Frame {
+	if len(names) <= len(_SmallFrame{}.buffer) {
+		f := &_SmallFrame{}
+		f.names = names
+		f.values = f.buffer[:0:len(names)]
+		return &f._Frame
+	}
+	return &_Frame{names: names, values: make([]Node, 0, len(names))}
+}
+
+// index returns the index of name or -1. When the same name is bound
+// twice, the latter one is found.
+func (f *_Frame) index(name Symbol) int {
+	for i := len(f.values) - 1; i >= 0; i-- {
+		if f.names[i] == name {
+			return i
+		}
+	}
+	return -1
+}
+
+func (f *_Frame) Get(name Symbol) (Node, bool) {
+	if i := f.index(name); i >= 0 {
+		return f.values[i], true
+	}
+	return Null, false
+}
+
+func (f *_Frame) Set(name Symbol, value Node) error {
+	if i := f.index(name); i >= 0 {
+		f.values[i] = value
+		return nil
+	}
+	return &_UndefinedEntity{name: name, space: symVariable}
+}
+
+func (f *_Frame) Range(callback func(Symbol, Node) bool) {
+	for i, value := range f.values {
+		if !callback(f.names[i], value) {
+			return
+		}
+	}
+}
+
+// bind binds the next variable. name has to be names[len(values)] unless
+// the names are given one by one by the interpreter.
+func (f *_Frame) bind(name Symbol, value Node) {
+	if len(f.values) == len(f.names) {
+		f.names = append(f.names, name)
+	}
+	f.values = append(f.values, value)
+}
+
+// frameOf returns the frame of the depth-th outer world of w. The compiled
+// code calls it with the depth resolved at the compile time.
+func frameOf(w *World, depth int) *_Frame {
+	for ; depth > 0; depth-- {
+		w = w.parent
+	}
+	return w.vars.(*_Frame)
+}
@@ -0,0 +1,27 @@
+package gmnlisp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestLetScope(t *testing.T) {
+	vars := Variables{NewSymbol("x"): Integer(1)}
+	w := New().Let(vars)
+	value, err := w.Interpret(context.TODO(), `
+		(defun add-x (n)
+			(let ((y (+ n x)))
+				(setq x y)
+				y))
+		(add-x 2)
+		(add-x 3)`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !value.Equals(Integer(6), EQUAL) {
+		t.Fatalf("got %v", value)
+	}
+	if x := vars[NewSymbol("x")]; !x.Equals(Integer(6), EQUAL) {
+		t.Fatalf("x was %v", x)
+	}
+}
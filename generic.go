@@ -4,12 +4,99 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
 )
 
+// _NextMethod calls the next most specific method with the same arguments.
+// It is nil when there is no next method.
+type _NextMethod func(context.Context, *World) (Node, error)
+
 type _Method struct {
-	restType Class
-	types    []Class
-	method   func(context.Context, *World, []Node) (Node, error)
+	// qualifier is one of :before, :after and :around, or nil for the primary methods.
+	qualifier Node
+	restType  Class
+	types     []Class
+	method    func(context.Context, *World, []Node, _NextMethod) (Node, error)
+}
+
+var (
+	kwBefore = NewKeyword(":before")
+	kwAfter  = NewKeyword(":after")
+	kwAround = NewKeyword(":around")
+)
+
+var methodClass = registerNewAbstractClass[*_Method]("<standard-method>")
+
+func (m *_Method) ClassOf() Class {
+	return methodClass
+}
+
+func (m *_Method) Equals(other Node, _ EqlMode) bool {
+	o, ok := other.(*_Method)
+	return ok && o == m
+}
+
+func (m *_Method) String() string {
+	var buffer strings.Builder
+	buffer.WriteString("{*_Method}")
+	if m.qualifier != nil {
+		buffer.WriteString(m.qualifier.String())
+	}
+	buffer.WriteString(m.lambdaList().String())
+	return buffer.String()
+}
+
+// Qualifier returns one of :before, :after and :around, or nil for
+// the primary methods.
+func (m *_Method) Qualifier() Node {
+	return m.qualifier
+}
+
+// Specializers returns the classes of the required parameters.
+func (m *_Method) Specializers() []Class {
+	return append([]Class{}, m.types...)
+}
+
+// RestSpecializer returns the class of the :rest parameter, or nil when
+// the method does not have it.
+func (m *_Method) RestSpecializer() Class {
+	return m.restType
+}
+
+// lambdaList returns the specializers as (CLASS... :rest CLASS).
+func (m *_Method) lambdaList() Node {
+	var result Node = Null
+	if m.restType != nil {
+		result = List(colonRest, m.restType)
+	}
+	for i := len(m.types) - 1; i >= 0; i-- {
+		result = &Cons{Car: m.types[i], Cdr: result}
+	}
+	return result
+}
+
+// specializer returns the class of the i-th parameter.
+func (m *_Method) specializer(i int) Class {
+	if i < len(m.types) {
+		return m.types[i]
+	}
+	return m.restType
+}
+
+func (m *_Method) sameSpecializers(other *_Method) bool {
+	if len(m.types) != len(other.types) {
+		return false
+	}
+	for i, t := range m.types {
+		if !t.Equals(other.types[i], STRICT) {
+			return false
+		}
+	}
+	if m.restType == nil || other.restType == nil {
+		return m.restType == nil && other.restType == nil
+	}
+	return m.restType.Equals(other.restType, STRICT)
 }
 
 func (m *_Method) canCallWith(values []Node) bool {
@@ -50,6 +137,162 @@ type _Generic struct {
 	methods []*_Method
 }
 
+// addMethod adds m to the generic function. The method which has the same
+// qualifier and specializers as m is replaced.
+func (c *_Generic) addMethod(m *_Method) {
+	for i, m1 := range c.methods {
+		if m1.qualifier == m.qualifier && m1.sameSpecializers(m) {
+			c.methods[i] = m
+			return
+		}
+	}
+	c.methods = append(c.methods, m)
+}
+
+// applicableMethods returns the methods which can be called with values
+// ordered from the most specific one.
+func (c *_Generic) applicableMethods(values []Node) []*_Method {
+	var methods []*_Method
+	for i := len(c.methods) - 1; i >= 0; i-- {
+		if m := c.methods[i]; m.canCallWith(values) {
+			methods = append(methods, m)
+		}
+	}
+	cpls := make([][]Class, len(values))
+	precedence := func(m *_Method, i int) int {
+		if cpls[i] == nil {
+			cpl, err := classPrecedenceList(values[i].ClassOf())
+			if err != nil {
+				cpl = []Class{values[i].ClassOf(), objectClass}
+			}
+			cpls[i] = cpl
+		}
+		if p := indexOfClass(cpls[i], m.specializer(i)); p >= 0 {
+			return p
+		}
+		return len(cpls[i])
+	}
+	sort.SliceStable(methods, func(i, j int) bool {
+		for k := range values {
+			pi := precedence(methods[i], k)
+			pj := precedence(methods[j], k)
+			if pi != pj {
+				return pi < pj
+			}
+		}
+		return false
+	})
+	return methods
+}
+
+// chainMethods calls methods[0] whose call-next-method calls methods[1:]
+// and last after them. When methods is empty, it calls last.
+func chainMethods(ctx context.Context, w *World, methods []*_Method, args []Node, last _NextMethod) (Node, error) {
+	if len(methods) <= 0 {
+		return last(ctx, w)
+	}
+	next := last
+	if len(methods) > 1 {
+		next = func(ctx context.Context, w *World) (Node, error) {
+			return chainMethods(ctx, w, methods[1:], args, last)
+		}
+	}
+	return methods[0].method(ctx, w, args, next)
+}
+
+// callMethods calls the applicable methods with the standard method
+// combination: the :around methods, the :before methods from the most
+// specific one, the primary methods and the :after methods from the least
+// specific one. It returns false when no primary method is applicable.
+func callMethods(ctx context.Context, w *World, methods []*_Method, args []Node) (Node, bool, error) {
+	var around, before, primary, after []*_Method
+	for _, m := range methods {
+		switch m.qualifier {
+		case kwAround:
+			around = append(around, m)
+		case kwBefore:
+			before = append(before, m)
+		case kwAfter:
+			after = append(after, m)
+		default:
+			primary = append(primary, m)
+		}
+	}
+	if len(primary) <= 0 {
+		return nil, false, nil
+	}
+	result, err := chainMethods(ctx, w, around, args, func(ctx context.Context, w *World) (Node, error) {
+		for _, m := range before {
+			if _, err := m.method(ctx, w, args, nil); err != nil {
+				return nil, err
+			}
+		}
+		result, err := chainMethods(ctx, w, primary, args, nil)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(after) - 1; i >= 0; i-- {
+			if _, err := after[i].method(ctx, w, args, nil); err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	})
+	return result, true, err
+}
+
+// Method is a method of a generic function.
+type Method interface {
+	Node
+	// Qualifier returns one of :before, :after and :around, or nil for
+	// the primary methods.
+	Qualifier() Node
+	// Specializers returns the classes of the required parameters.
+	Specializers() []Class
+	// RestSpecializer returns the class of the rest parameter or nil.
+	RestSpecializer() Class
+}
+
+// GenericFunction is the function defined with defgeneric.
+type GenericFunction interface {
+	Callable
+	Name() Symbol
+	// Methods returns the methods in the order of the definition.
+	Methods() []Method
+}
+
+var _ GenericFunction = &_Generic{}
+
+func (c *_Generic) Name() Symbol {
+	return c.Symbol
+}
+
+// Methods returns the methods of the generic function in the order of
+// the definition.
+func (c *_Generic) Methods() []Method {
+	methods := make([]Method, len(c.methods))
+	for i, m := range c.methods {
+		methods[i] = m
+	}
+	return methods
+}
+
+// GenericFunctions returns the generic functions defined globally sorted
+// by their names.
+func (w *World) GenericFunctions() []GenericFunction {
+	var generics []GenericFunction
+	w.defun.Range(func(_ Symbol, f Callable) bool {
+		if g, ok := f.(*_Generic); ok {
+			generics = append(generics, g)
+		}
+		return true
+	})
+	sort.Slice(generics, func(i, j int) bool {
+		return generics[i].Name().String() < generics[j].Name().String()
+	})
+	return generics
+}
+
 func cmdDefGeneric(ctx context.Context, w *World, node Node) (Node, error) {
 	_name, node, err := Shift(node)
 	if err != nil {
@@ -100,11 +343,12 @@ func cmdDefGeneric(ctx context.Context, w *World, node Node) (Node, error) {
 		}
 		argc++
 	}
-	w.defun.Set(name, &_Generic{Symbol: name, argc: argc, rest: hasRest})
+	w.defineFunc(name, &_Generic{Symbol: name, argc: argc, rest: hasRest})
 	return name, nil
 }
 
 func (c *_Generic) Call(ctx context.Context, w *World, node Node) (Node, error) {
+	callSite := w.callSite
 	values := []Node{}
 	for IsSome(node) {
 		var v Node
@@ -115,10 +359,11 @@ func (c *_Generic) Call(ctx context.Context, w *World, node Node) (Node, error)
 		}
 		values = append(values, v)
 	}
-	for i := len(c.methods) - 1; i >= 0; i-- {
-		if m := c.methods[i]; m.canCallWith(values) {
-			return m.method(ctx, w, values)
-		}
+	depth := w.enterFrame(c.Symbol, values, callSite)
+	result, ok, err := callMethods(ctx, w, c.applicableMethods(values), values)
+	err = w.leaveFrame(depth, err)
+	if ok || err != nil {
+		return result, err
 	}
 	return callHandler[FunctionRef](ctx, w, false, &_UndefinedEntity{
 		name:  c.Symbol,
@@ -151,12 +396,21 @@ func cmdDefMethod(ctx context.Context, w *World, node Node) (Node, error) {
 	if err != nil {
 		return nil, fmt.Errorf("%w: %#v", err, name.String())
 	}
+	method := &_Method{}
+	if cons, ok := node.(*Cons); ok {
+		if kw, ok := cons.Car.(Keyword); ok {
+			if kw != kwBefore && kw != kwAfter && kw != kwAround {
+				return raiseProgramError(ctx, w, fmt.Errorf("%s: invalid method qualifier", kw.String()))
+			}
+			method.qualifier = kw
+			node = cons.Cdr
+		}
+	}
 	params, code, err := Shift(node)
 	if err != nil {
 		return nil, err
 	}
 
-	method := &_Method{}
 	paramNames := []Symbol{}
 	var restName Symbol
 	for IsSome(params) {
@@ -196,6 +450,11 @@ func cmdDefMethod(ctx context.Context, w *World, node Node) (Node, error) {
 			method.restType = type1
 			break
 		}
+		if pn1, ok := t1.(Symbol); ok {
+			paramNames = append(paramNames, pn1)
+			method.types = append(method.types, objectClass)
+			continue
+		}
 		var _pn1 Node
 		_pn1, t1, err = Shift(t1)
 		if err != nil {
@@ -220,25 +479,80 @@ func cmdDefMethod(ctx context.Context, w *World, node Node) (Node, error) {
 		}
 		method.types = append(method.types, type1)
 	}
-	method.method = func(ctx context.Context, w *World, args []Node) (Node, error) {
-		vars := Variables{}
+	names := paramNames
+	if restName != nulSymbol {
+		names = append(names, restName)
+	}
+	// The body is evaluated in the world where the method is defined, with
+	// one scope which has both the parameters and the next methods, so
+	// that the recursive calls do not make the scopes deeper.
+	defined := w
+	method.method = func(ctx context.Context, _ *World, args []Node, next _NextMethod) (Node, error) {
+		frame := newFrame(names)
 		for i, v := range args {
 			if i >= len(paramNames) {
-				var rest Node = Null
-				for j := len(args) - 1; j >= i; j-- {
-					rest = &Cons{Car: args[j], Cdr: rest}
-				}
-				vars[restName] = rest
 				break
 			}
-			vars[paramNames[i]] = v
+			frame.values = append(frame.values, v)
 		}
-		return Progn(ctx, w.Let(vars), code)
+		if restName != nulSymbol {
+			frame.values = append(frame.values, List(args[len(frame.values):]...))
+		}
+		return Progn(ctx, &World{
+			parent: defined,
+			vars:   frame,
+			funcs:  _NextMethodScope{next: next},
+			shared: defined.shared,
+		}, code)
 	}
-	generic.methods = append(generic.methods, method)
+	generic.addMethod(method)
 	return name, nil
 }
 
+var (
+	symCallNextMethod = NewSymbol("call-next-method")
+	symNextMethodP    = NewSymbol("next-method-p")
+)
+
+// _NextMethodScope is the FuncScope which has call-next-method and
+// next-method-p in the body of a method.
+type _NextMethodScope struct {
+	next _NextMethod
+}
+
+func (s _NextMethodScope) Get(name Symbol) (Callable, bool) {
+	switch name {
+	case symCallNextMethod:
+		return Function0(s.callNextMethod), true
+	case symNextMethodP:
+		return Function0(s.nextMethodP), true
+	}
+	return nil, false
+}
+
+// Set does nothing because the functions of a method can not be redefined.
+func (s _NextMethodScope) Set(Symbol, Callable) {}
+
+func (s _NextMethodScope) Range(callback func(Symbol, Callable) bool) {
+	if callback(symCallNextMethod, Function0(s.callNextMethod)) {
+		callback(symNextMethodP, Function0(s.nextMethodP))
+	}
+}
+
+func (s _NextMethodScope) callNextMethod(ctx context.Context, w *World) (Node, error) {
+	if s.next == nil {
+		return raiseControlError(ctx, w, ErrNoNextMethod)
+	}
+	return s.next(ctx, w)
+}
+
+func (s _NextMethodScope) nextMethodP(context.Context, *World) (Node, error) {
+	if s.next == nil {
+		return Null, nil
+	}
+	return True, nil
+}
+
 func funGenericFunctionP(ctx context.Context, w *World, arg Node) (Node, error) {
 	f, err := ExpectFunction(ctx, w, arg)
 	if err != nil {
@@ -249,3 +563,43 @@ func funGenericFunctionP(ctx context.Context, w *World, arg Node) (Node, error)
 	}
 	return Null, nil
 }
+
+func funGenericFunctionMethods(ctx context.Context, w *World, arg Node) (Node, error) {
+	f, err := ExpectFunction(ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	g, ok := f.(*_Generic)
+	if !ok {
+		return callHandler[Node](ctx, w, false, &DomainError{
+			Object:        arg,
+			ExpectedClass: functionRefClassObject,
+		})
+	}
+	var result ListBuilder
+	for _, m := range g.methods {
+		result.Add(ctx, w, m)
+	}
+	return result.Sequence(), nil
+}
+
+// funMethodSpecializers implements (method-specializers METHOD). The class of
+// the :rest parameter follows :rest as in the lambda list of defmethod.
+func funMethodSpecializers(ctx context.Context, w *World, arg Node) (Node, error) {
+	m, err := ExpectClass[*_Method](ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	return m.lambdaList(), nil
+}
+
+func funMethodQualifiers(ctx context.Context, w *World, arg Node) (Node, error) {
+	m, err := ExpectClass[*_Method](ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	if m.qualifier == nil {
+		return Null, nil
+	}
+	return List(m.qualifier), nil
+}
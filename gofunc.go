@@ -0,0 +1,181 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	worldType   = reflect.TypeOf((*World)(nil))
+)
+
+// GoFunc makes a Function from the ordinary Go function f such as
+// func(string, int) (string, error). f may receive context.Context and
+// *World before the other parameters and may be variadic. The arguments
+// are converted by the same rules as FromNode to the types of the
+// parameters and DomainError is raised when they do not match. The results
+// are converted back to Node by ToNode except that rune is converted to
+// the character. When the last result is error, it is returned as the
+// error of the call, and so is the panic of f. GoFunc panics if f is not
+// a function.
+func GoFunc(f any) *Function {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func {
+		panic(fmt.Sprintf("gmnlisp.GoFunc: %T is not a function", f))
+	}
+	in := 0
+	useContext := in < ft.NumIn() && ft.In(in) == contextType
+	if useContext {
+		in++
+	}
+	useWorld := in < ft.NumIn() && ft.In(in) == worldType
+	if useWorld {
+		in++
+	}
+	params := make([]reflect.Type, 0, ft.NumIn()-in)
+	for ; in < ft.NumIn(); in++ {
+		params = append(params, ft.In(in))
+	}
+	var variadic reflect.Type
+	if ft.IsVariadic() {
+		variadic = params[len(params)-1].Elem()
+		params = params[:len(params)-1]
+	}
+	results := ft.NumOut()
+	returnsError := results > 0 && ft.Out(results-1) == errorType
+	if returnsError {
+		results--
+	}
+
+	F := func(ctx context.Context, w *World, args []Node) (Node, error) {
+		if len(args) < len(params) {
+			return raiseProgramError(ctx, w, ErrTooFewArguments)
+		}
+		if variadic == nil && len(args) > len(params) {
+			return raiseProgramError(ctx, w, ErrTooManyArguments)
+		}
+		in := make([]reflect.Value, 0, ft.NumIn()+len(args)-len(params))
+		if useContext {
+			in = append(in, reflect.ValueOf(ctx))
+		}
+		if useWorld {
+			in = append(in, reflect.ValueOf(w))
+		}
+		for i, arg := range args {
+			t := variadic
+			if i < len(params) {
+				t = params[i]
+			}
+			v, err := toGoValue(ctx, w, arg, t)
+			if err != nil {
+				return nil, err
+			}
+			in = append(in, v)
+		}
+		out, err := callGo(fv, in)
+		if err != nil {
+			return nil, err
+		}
+		if returnsError {
+			if err, _ := out[results].Interface().(error); err != nil {
+				return nil, err
+			}
+		}
+		switch results {
+		case 0:
+			return Null, nil
+		case 1:
+			return resultToNode(out[0])
+		}
+		var list ListBuilder
+		for _, v := range out[:results] {
+			value, err := resultToNode(v)
+			if err != nil {
+				return nil, err
+			}
+			if err := list.Add(ctx, w, value); err != nil {
+				return nil, err
+			}
+		}
+		return list.Sequence(), nil
+	}
+	if variadic != nil {
+		return &Function{Min: len(params), F: F}
+	}
+	return &Function{C: len(params), F: F}
+}
+
+var runeType = reflect.TypeOf(rune(0))
+
+// resultToNode converts the result of the function given to GoFunc.
+// rune is converted to the character while ToNode converts int32 to
+// the integer.
+func resultToNode(v reflect.Value) (Node, error) {
+	if v.Type() == runeType {
+		return Rune(v.Int()), nil
+	}
+	return toNode(v)
+}
+
+// callGo calls fv with in and returns the panic of fv as the error.
+func callGo(fv reflect.Value, in []reflect.Value) (out []reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("panic: %w", e)
+			} else {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}
+	}()
+	return fv.Call(in), nil
+}
+
+// toGoValue converts node to the value of the type t. When node can not
+// be converted, it raises DomainError and retries with the value given
+// by the handler.
+func toGoValue(ctx context.Context, w *World, node Node, t reflect.Type) (reflect.Value, error) {
+	v := reflect.New(t).Elem()
+	err := fromNode(node, v)
+	var domainError *DomainError
+	if !errors.As(err, &domainError) {
+		return v, err
+	}
+	value, err := callHandler[Node](ctx, w, true, domainError)
+	if err != nil {
+		return v, err
+	}
+	return toGoValue(ctx, w, value, t)
+}
+
+// Call calls the function named name, which may be defined by the script,
+// with args converted by ToNode. The arguments are not evaluated again.
+func (w *World) Call(ctx context.Context, name Symbol, args ...any) (Node, error) {
+	f, err := w.GetFunc(name)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]Node, len(args))
+	for i, arg := range args {
+		if nodes[i], err = ToNode(arg); err != nil {
+			return nil, err
+		}
+	}
+	return f.Call(ctx, w, UnevalList(nodes...))
+}
+
+// CallAs calls the function named name as World.Call and converts its
+// result to T by FromNode.
+func CallAs[T any](ctx context.Context, w *World, name Symbol, args ...any) (T, error) {
+	var result T
+	value, err := w.Call(ctx, name, args...)
+	if err != nil {
+		return result, err
+	}
+	err = FromNode(value, &result)
+	return result, err
+}
@@ -0,0 +1,94 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestGoFunc(t *testing.T) {
+	w := New().Flet(Functions{
+		NewSymbol("repeat"): GoFunc(strings.Repeat),
+		NewSymbol("sum"): GoFunc(func(ctx context.Context, values ...float64) float64 {
+			total := 0.0
+			for _, v := range values {
+				total += v
+			}
+			return total
+		}),
+		NewSymbol("fields"): GoFunc(strings.Fields),
+		NewSymbol("join"):   GoFunc(strings.Join),
+		NewSymbol("div"): GoFunc(func(a, b int) (int, int, error) {
+			if b == 0 {
+				return 0, 0, errors.New("zero")
+			}
+			return a / b, a % b, nil
+		}),
+		NewSymbol("upper"): GoFunc(func(r rune) rune { return r - 'a' + 'A' }),
+		NewSymbol("ok"):    GoFunc(func(b bool) bool { return !b }),
+	})
+	for code, expect := range map[string]Node{
+		`(repeat "ab" 3)`:          String("ababab"),
+		`(sum 1 2.5 3)`:            Float(6.5),
+		`(sum)`:                    Float(0),
+		`(fields " a b  c ")`:      List(String("a"), String("b"), String("c")),
+		`(join '("a" "b") "-")`:    String("a-b"),
+		`(join #("a" "b" "c") "")`: String("abc"),
+		`(div 7 2)`:                List(Integer(3), Integer(1)),
+		`(upper #\a)`:              Rune('A'),
+		`(ok nil)`:                 True,
+		`(catch 'c (with-handler (lambda (c) (throw 'c (class-of c))) (repeat 1 2)))`: domainErrorClass,
+		`(with-handler (lambda (c) (continue-condition c "x")) (repeat 1 2))`:         String("xx"),
+	} {
+		if e := w.Assert(code, expect); e != "" {
+			t.Fatal(e)
+		}
+	}
+	// strings.Repeat panics with the negative count
+	for _, code := range []string{`(repeat "a")`, `(repeat "a" 1 2)`, `(div 1 0)`, `(repeat "a" -1)`, `(join '("a" . "b") "")`} {
+		if _, err := w.Interpret(context.TODO(), code); err == nil {
+			t.Fatalf("%s: no error", code)
+		}
+	}
+}
+
+func TestCall(t *testing.T) {
+	w := New()
+	w.DefineStruct(testOrder{})
+	if _, err := w.Interpret(context.TODO(), `
+		(defun on-order-created (order rate)
+			(* (test-order-amount order) rate))
+		(defun tags (n) (create-list n "x"))`); err != nil {
+		t.Fatal(err.Error())
+	}
+	ctx := context.TODO()
+	total, err := CallAs[float64](ctx, w, NewSymbol("on-order-created"), &testOrder{Amount: 10}, 2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if total != 20 {
+		t.Fatalf("got %v", total)
+	}
+	tags, err := CallAs[[]string](ctx, w, NewSymbol("tags"), 2)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(tags) != 2 || tags[0] != "x" {
+		t.Fatalf("got %#v", tags)
+	}
+	// the arguments are not evaluated
+	value, err := w.Call(ctx, NewSymbol("list"), NewSymbol("a"), List(NewSymbol("b")))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !value.Equals(List(NewSymbol("a"), List(NewSymbol("b"))), EQUAL) {
+		t.Fatalf("got %v", value)
+	}
+	if _, err := CallAs[int](ctx, w, NewSymbol("tags"), 1); err == nil {
+		t.Fatal("the result was converted to int")
+	}
+	if _, err := w.Call(ctx, NewSymbol("no-such-function")); err == nil {
+		t.Fatal("an undefined function was called")
+	}
+}
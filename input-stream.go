@@ -3,13 +3,16 @@ package gmnlisp
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 )
 
 type inputStream struct {
 	_Reader  *bufio.Reader
-	file     *os.File
+	file     fs.File
 	isClosed bool
 }
 
@@ -35,10 +38,7 @@ func (t *inputStream) ClassOf() Class {
 
 func (t *inputStream) Equals(other Node, _ EqlMode) bool {
 	o, ok := other.(*inputStream)
-	if !ok {
-		return false
-	}
-	return t.file.Fd() == o.file.Fd()
+	return ok && t == o
 }
 
 func (t *inputStream) String() string {
@@ -70,8 +70,17 @@ func (i *inputStream) IsClosed() bool {
 	return i.isClosed
 }
 
+var errNotSeekable = errors.New("the stream can not seek")
+
+func (i *inputStream) seek(offset int64, whence int) (int64, error) {
+	if s, ok := i.file.(io.Seeker); ok {
+		return s.Seek(offset, whence)
+	}
+	return 0, errNotSeekable
+}
+
 func (i *inputStream) FilePosition() (int64, error) {
-	z, err := i.file.Seek(0, os.SEEK_CUR)
+	z, err := i.seek(0, io.SeekCurrent)
 	if err != nil {
 		return 0, err
 	}
@@ -79,7 +88,7 @@ func (i *inputStream) FilePosition() (int64, error) {
 }
 
 func (i *inputStream) SetFilePosition(n int64) (int64, error) {
-	ret, err := i.file.Seek(n, os.SEEK_SET)
+	ret, err := i.seek(n, io.SeekStart)
 	i._Reader.Reset(i.file)
 	return ret, err
 }
@@ -88,7 +97,7 @@ func (i *inputStream) QueryStreamReady() (Node, error) {
 	if i.isClosed {
 		return Null, StreamError{Stream: i}
 	}
-	if _, err := i.file.Seek(0, os.SEEK_CUR); err != nil {
+	if _, err := i.seek(0, io.SeekCurrent); err != nil {
 		return Null, nil
 	}
 	return True, nil
@@ -99,7 +108,10 @@ func openInputFile(ctx context.Context, w *World, fname Node) (*inputStream, err
 	if err != nil {
 		return nil, err
 	}
-	reader, err := os.Open(filename.String())
+	if err := w.RequireCapability(ctx, CapFileRead, "open-input-file"); err != nil {
+		return nil, err
+	}
+	reader, err := w.openFile(filename.String())
 	if err != nil {
 		return nil, err
 	}
@@ -264,7 +264,10 @@ func openOutputFile(ctx context.Context, w *World, fnameNode Node) (*outputStrea
 	if err != nil {
 		return nil, err
 	}
-	writer, err := os.Create(filename.String())
+	if err := w.RequireCapability(ctx, CapFileWrite, "open-output-file"); err != nil {
+		return nil, err
+	}
+	writer, err := w.createFile(ctx, filename.String(), os.O_RDWR|os.O_CREATE|os.O_TRUNC, "open-output-file")
 	if err != nil {
 		return nil, err
 	}
@@ -307,8 +310,11 @@ func funProbeFile(ctx context.Context, w *World, arg Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := w.RequireCapability(ctx, CapFileRead, "probe-file"); err != nil {
+		return nil, err
+	}
 	fname := _fname.String()
-	_, err = os.Stat(fname)
+	_, err = w.statFile(fname)
 	if err != nil {
 		return Null, nil
 	}
@@ -328,7 +334,10 @@ func funFileLength(ctx context.Context, w *World, first, second Node) (Node, err
 	}
 	n := int64(_n)
 
-	stat, err := os.Stat(fname)
+	if err := w.RequireCapability(ctx, CapFileRead, "file-length"); err != nil {
+		return nil, err
+	}
+	stat, err := w.statFile(fname)
 	if err != nil {
 		return Null, nil
 	}
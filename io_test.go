@@ -2,6 +2,7 @@ package gmnlisp
 
 import (
 	"context"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
@@ -71,3 +72,17 @@ func TestFileLength(t *testing.T) {
 		t.Fatal(eStr)
 	}
 }
+
+// sliceFile is the fs.File which can not be compared with ==.
+type sliceFile struct {
+	fs.File
+	data []byte
+}
+
+func TestInputStreamEquals(t *testing.T) {
+	a := &inputStream{file: sliceFile{}}
+	b := &inputStream{file: sliceFile{}}
+	if !a.Equals(a, STRICT) || a.Equals(b, STRICT) {
+		t.Fatal("the streams are compared by their identities")
+	}
+}
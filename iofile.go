@@ -99,8 +99,11 @@ func (i *IOFile) QueryStreamReady() (Node, error) {
 	return True, nil
 }
 
-func newIoFile(fname string) (*IOFile, error) {
-	f, err := os.OpenFile(fname, os.O_RDWR|os.O_CREATE, 0644)
+func newIoFile(ctx context.Context, w *World, fname string) (*IOFile, error) {
+	if err := w.RequireCapability(ctx, CapFileRead|CapFileWrite, "open-io-file"); err != nil {
+		return nil, err
+	}
+	f, err := w.createFile(ctx, fname, os.O_RDWR|os.O_CREATE, "open-io-file")
 	if err != nil {
 		return nil, err
 	}
@@ -116,7 +119,7 @@ func funOpenIoFile(ctx context.Context, w *World, args []Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	return newIoFile(string(fname))
+	return newIoFile(ctx, w, string(fname))
 }
 
 func cmdWithOpenIoFile(ctx context.Context, w *World, list Node) (Node, error) {
@@ -140,7 +143,7 @@ func cmdWithOpenIoFile(ctx context.Context, w *World, list Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	stream, err := newIoFile(string(filename))
+	stream, err := newIoFile(ctx, w, string(filename))
 	if err != nil {
 		return nil, err
 	}
@@ -16,6 +16,12 @@ type _Lambda struct {
 	name    Symbol
 	rest    Symbol
 	lexical *World
+	// names are the variables bound by the call in the order of the frame.
+	names []Symbol
+	// body is the code compiled at the first call.
+	body _Compiled
+	// program is the bytecode compiled by (compile).
+	program *_Program
 }
 
 func cmdLambda(ctx context.Context, w *World, node Node) (Node, error) {
@@ -82,85 +88,27 @@ func getParameterList(ctx context.Context, w *World, node Node) (*_Parameters, e
 	}, nil
 }
 
-func expandMacroOne(ctx context.Context, w *World, value Node) Node {
-	cons, ok := value.(*Cons)
-	if !ok {
-		return nil
-	}
-	symbol, ok := cons.Car.(Symbol)
-	if !ok {
-		car := expandMacroOne(ctx, w, cons.Car)
-		cdr := expandMacroOne(ctx, w, cons.Cdr)
-		return &Cons{Car: car, Cdr: cdr}
-	}
-	macro, ok := w.macro[symbol]
-	if !ok {
-		f, err := w.GetFunc(symbol)
-		if err != nil {
-			return nil
-		}
-		ls, ok := f.(*LispString)
-		if !ok {
-			return nil
-		}
-		fm, err := ls.Eval(ctx, w)
-		if err != nil {
-			return nil
-		}
-		fmacro, ok := fm.(FunctionRef)
-		if !ok {
-			return nil
-		}
-		macro, ok = fmacro.value.(*_Macro)
-		if !ok {
-			return nil
-		}
-	}
-	expandCode, err := macro.expand(ctx, w, cons.Cdr)
-	if err != nil {
-		println(err.Error())
-		return nil
-	}
-	if x := expandMacroOne(ctx, w, expandCode); x != nil {
-		return x
-	}
-	return expandCode
-}
-
-func expandMacroInList(ctx context.Context, w *World, code Node) (Node, error) {
-	var newCode Node
-	for IsSome(code) {
-		var value Node
-		var err error
-		value, code, err = Shift(code)
-		if err != nil {
-			return nil, err
-		}
-		if c := expandMacroOne(ctx, w, value); c != nil {
-			value = c
-		}
-		newCode = &Cons{Car: value, Cdr: newCode}
-	}
-	return NReverse(ctx, w, newCode)
-}
-
 func newLambda(ctx context.Context, w *World, node Node, blockName Symbol) (Callable, error) {
 	p, err := getParameterList(ctx, w, node)
 	if err != nil {
 		return nil, err
 	}
-	code := p.code
-	if c, err := expandMacroInList(ctx, w, code); err == nil {
-		code = c
-	} else {
-		println(err.Error())
+	names := make([]Symbol, 0, len(p.param)+1)
+	for _, name := range p.param {
+		if name != slashSymbol {
+			names = append(names, name)
+		}
+	}
+	if p.rest != nulSymbol {
+		names = append(names, p.rest)
 	}
 	return &_Lambda{
 		param:   p.param,
-		code:    code,
+		code:    p.code,
 		name:    blockName,
 		rest:    p.rest,
 		lexical: w,
+		names:   names[:len(names):len(names)],
 	}, nil
 }
 
@@ -202,10 +150,12 @@ func (t _Lambda) String() string {
 	return buffer.String()
 }
 
-var trace = map[Symbol]int{}
-
+// _ErrTailRecOpt is returned by the call at the tail position of the
+// compiled body instead of calling callee, so that the loop of apply calls
+// it without growing the stack.
 type _ErrTailRecOpt struct {
-	params Node
+	callee *_Lambda
+	args   []Node
 }
 
 func (*_ErrTailRecOpt) Error() string {
@@ -214,102 +164,111 @@ func (*_ErrTailRecOpt) Error() string {
 
 func (L *_Lambda) Call(ctx context.Context, w *World, n Node) (Node, error) {
 	if err := checkContext(ctx); err != nil {
-		// return nil,fmt.Errorf("%w\n\t%s",err,L.name)
 		return nil, err
 	}
-	lexical := Variables{}
-	foundSlash := false
-	traceCount, traceDo := trace[L.name]
-	if traceDo {
-		fmt.Fprintf(os.Stderr, "[%d: (%s", traceCount, L.name)
-		trace[L.name]++
-		defer func() {
-			trace[L.name]--
-		}()
+	args := make([]Node, 0, len(L.param))
+	for IsSome(n) {
+		var value Node
+		var err error
+		value, n, err = w.ShiftAndEvalCar(ctx, n)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, value)
 	}
+	return L.apply(ctx, w, args)
+}
+
+// bind makes the lexical variables of the parameters given args.
+// The parameters after the slash are initialized with nil.
+func (L *_Lambda) bind(ctx context.Context, w *World, args []Node) (*_Frame, error) {
+	lexical := newFrame(L.names)
+	i := 0
+	foundSlash := false
 	for _, name := range L.param {
 		if name == slashSymbol {
 			foundSlash = true
 			continue
 		}
 		if foundSlash {
-			lexical[name] = Null
+			lexical.bind(name, Null)
 			continue
 		}
-		var err error
-		var value Node
-		value, n, err = w.ShiftAndEvalCar(ctx, n)
-		if err != nil {
-			// return nil,fmt.Errorf("%w\n\t%s",err,L.name)
-			return nil, err
-		}
-		lexical[name] = value
-		if traceDo {
-			fmt.Fprintf(os.Stderr, " %#v", value)
+		if i >= len(args) {
+			return nil, ErrTooFewArguments
 		}
+		lexical.bind(name, args[i])
+		i++
 	}
-	if traceDo {
-		fmt.Fprintln(os.Stderr, ")]")
+	if L.rest != nulSymbol {
+		lexical.bind(L.rest, List(args[i:]...))
+	} else if i < len(args) {
+		_, err := raiseProgramError(ctx, w, ErrTooManyArguments)
+		return nil, err
 	}
+	return lexical, nil
+}
 
-	if IsSome(n) && L.rest == nulSymbol {
-		return raiseProgramError(ctx, w, ErrTooManyArguments)
+// apply calls L with the evaluated arguments.
+func (L *_Lambda) apply(ctx context.Context, w *World, args []Node) (Node, error) {
+	callSite := w.callSite
+	var traceCount int
+	var traceDo bool
+	if len(w.trace) > 0 {
+		traceCount, traceDo = w.trace[L.name]
 	}
-	if L.rest != nulSymbol {
-		var values Node = nil
-		for IsSome(n) {
-			var value Node
-			var err error
-
-			value, n, err = w.ShiftAndEvalCar(ctx, n)
-			if err != nil {
-				return nil, err
-			}
-			values = &Cons{
-				Car: value,
-				Cdr: values,
+	if traceDo {
+		fmt.Fprintf(os.Stderr, "[%d: (%s", traceCount, L.name)
+		w.trace[L.name]++
+		defer func() {
+			w.trace[L.name]--
+		}()
+	}
+	lexical, err := L.bind(ctx, w, args)
+	if traceDo {
+		for i, name := range L.param {
+			if name == slashSymbol || i >= len(args) {
+				break
 			}
+			fmt.Fprintf(os.Stderr, " %#v", args[i])
 		}
-		var err error
-		lexical[L.rest], err = NReverse(ctx, w, values)
-		if err != nil {
-			return nil, err
-		}
+		fmt.Fprintln(os.Stderr, ")]")
 	}
-
+	if err != nil {
+		return nil, err
+	}
+	depth := w.enterFrame(L.name, args, callSite)
 	var result Node
-	var err error
-	for {
-		newWorld := L.lexical.Let(lexical)
-		result, err = prognWithTailRecOpt(ctx, newWorld, L.code, L.name)
+	// blocks are the names of the other lambdas called at the tail position.
+	var blocks []Symbol
+	for callee := L; ; {
+		if callee.body == nil {
+			callee.body = compileBody(ctx, callee)
+		}
+		result, err = callee.body(ctx, callee.lexical.Let(lexical))
 
-		var errTailRecOpt *_ErrTailRecOpt
-		if !errors.As(err, &errTailRecOpt) {
+		tail, ok := err.(*_ErrTailRecOpt)
+		if !ok {
 			break
 		}
-		n = errTailRecOpt.params
-		foundSlash = false
-		for _, name := range L.param {
-			if name == slashSymbol {
-				foundSlash = true
-				continue
-			}
-			if foundSlash {
-				lexical[name] = Null
-				continue
-			}
-			var err error
-			var value Node
-			value, n, err = Shift(n)
-			if err != nil {
-				// return nil,fmt.Errorf("%w\n\t%s",err,L.name)
-				return nil, err
-			}
-			lexical[name] = value
+		if err = checkContext(ctx); err != nil {
+			break
+		}
+		if tail.callee != callee {
+			callee = tail.callee
+			blocks = append(blocks, callee.name)
+			w.frames[depth].Name = callee.name
 		}
+		args = tail.args
+		lexical, err = callee.bind(ctx, w, args)
+		if err != nil {
+			break
+		}
+		w.frames[depth].Args = args
 	}
+	err = w.leaveFrame(depth, err)
 	var errEarlyReturns *_ErrEarlyReturns
-	if errors.As(err, &errEarlyReturns) && errEarlyReturns.Name == L.name {
+	if errors.As(err, &errEarlyReturns) && (errEarlyReturns.Name == L.name || containsSymbol(blocks, errEarlyReturns.Name)) {
 		return errEarlyReturns.Value, nil
 	}
 	if traceDo {
@@ -319,94 +278,18 @@ func (L *_Lambda) Call(ctx context.Context, w *World, n Node) (Node, error) {
 			result)
 	}
 	if err != nil {
-		// return nil, fmt.Errorf("%s: %w", L.name, err)
 		return nil, err
 	}
 	return result, nil
 }
 
-// If target.Car is current function symbol, then make and return an instance of _ErrTailRecOpt
-func testCarIsCurrFunc(ctx context.Context, w *World, target Node, currFunc Symbol) (err error) {
-	if currFunc.Id() < 0 {
-		return nil
-	}
-	cons, ok := target.(*Cons)
-	if !ok || IsNone(cons.Car) || !cons.Car.Equals(currFunc, EQUAL) {
-		return nil
-	}
-
-	// Tail call optimization
-	var params Node = cons.Cdr
-	var evaled Node
-	for IsSome(params) {
-		var value Node
-		value, params, err = w.ShiftAndEvalCar(ctx, params)
-		if err != nil {
-			return err
-		}
-		evaled = &Cons{Car: value, Cdr: evaled}
-	}
-	evaled, err = NReverse(ctx, w, evaled)
-	if err != nil {
-		panic(err.Error())
-	}
-	return &_ErrTailRecOpt{params: evaled}
-}
-
-var (
-	symIf    = NewSymbol("if")
-	symLet   = NewSymbol("let")
-	symLetX  = NewSymbol("let*")
-	symProgn = NewSymbol("progn")
-	symCond  = NewSymbol("cond")
-)
-
-// Evaluate the target considering the tail call optimization.
-func evalWithTailRecOpt(ctx context.Context, w *World, target Node, currFunc Symbol) (Node, error) {
-	if currFunc.Id() >= 0 {
-		if err := testCarIsCurrFunc(ctx, w, target, currFunc); err != nil {
-			return nil, err
-		}
-		if cons, ok := target.(*Cons); ok {
-			if symIf.Equals(cons.Car, EQUAL) {
-				return cmdIfWithTailRecOpt(ctx, w, cons.Cdr, currFunc)
-			}
-			if symLet.Equals(cons.Car, EQUAL) {
-				return cmdLetWithTailRecOpt(ctx, w, cons.Cdr, currFunc)
-			}
-			if symLetX.Equals(cons.Car, EQUAL) {
-				return cmdLetXWithTailRecOpt(ctx, w, cons.Cdr, currFunc)
-			}
-			if symProgn.Equals(cons.Car, EQUAL) {
-				return prognWithTailRecOpt(ctx, w, cons.Cdr, currFunc)
-			}
-			if symCond.Equals(cons.Car, EQUAL) {
-				return cmdCondWithTailRecOpt(ctx, w, cons.Cdr, currFunc)
-			}
+func containsSymbol(list []Symbol, symbol Symbol) bool {
+	for _, s := range list {
+		if s == symbol {
+			return true
 		}
 	}
-	return w.Eval(ctx, target)
-}
-
-func prognWithTailRecOpt(ctx context.Context, w *World, n Node, sym Symbol) (value Node, err error) {
-	value = Null
-	for IsSome(n) {
-		var first Node
-
-		first, n, err = Shift(n)
-		if err != nil {
-			return nil, err
-		}
-		if sym.Id() >= 0 && IsNone(n) {
-			value, err = evalWithTailRecOpt(ctx, w, first, sym)
-		} else {
-			value, err = w.Eval(ctx, first)
-		}
-		if err != nil {
-			return nil, err
-		}
-	}
-	return value, nil
+	return false
 }
 
 func cmdDefun(ctx context.Context, w *World, list Node) (Node, error) {
@@ -430,7 +313,10 @@ func cmdDefun(ctx context.Context, w *World, list Node) (Node, error) {
 			return raiseProgramError(ctx, w, fmt.Errorf("%s: special operator can not be changed", symbol.String()))
 		}
 	}
-	w.defun.Set(symbol, lambda)
+	if w.autoCompile {
+		compileLambda(ctx, lambda.(*_Lambda))
+	}
+	w.defineFunc(symbol, lambda)
 	return symbol, nil
 }
 
@@ -494,9 +380,7 @@ func (f SpecialF) Call(ctx context.Context, w *World, n Node) (Node, error) {
 
 func cmdTrace(ctx context.Context, w *World, list Node) (Node, error) {
 	// from CommonLisp
-	if len(trace) > 0 {
-		trace = map[Symbol]int{}
-	}
+	w.trace = map[Symbol]int{}
 	for IsSome(list) {
 		var symbolNode Node
 		var err error
@@ -509,7 +393,7 @@ func cmdTrace(ctx context.Context, w *World, list Node) (Node, error) {
 		if err != nil {
 			return nil, err
 		}
-		trace[symbol] = 0
+		w.trace[symbol] = 0
 	}
 	return Null, nil
 }
@@ -576,23 +460,27 @@ type Function struct {
 	Max int
 }
 
-func (f *Function) Call(ctx context.Context, w *World, list Node) (Node, error) {
-	if err := checkContext(ctx); err != nil {
-		return nil, err
-	}
-	max := math.MaxInt
+// bounds returns the minimum and the maximum number of the arguments.
+func (f *Function) bounds() (min, max int) {
+	max = math.MaxInt
 	if f.Max > 0 {
 		max = f.Max
 	} else if f.C > 0 {
 		max = f.C
 	}
-
-	min := 0
 	if f.C > 0 {
 		min = f.C
 	} else if f.Min > 0 {
 		min = f.Min
 	}
+	return min, max
+}
+
+func (f *Function) Call(ctx context.Context, w *World, list Node) (Node, error) {
+	if err := checkContext(ctx); err != nil {
+		return nil, err
+	}
+	min, max := f.bounds()
 
 	args := []Node{}
 	for IsSome(list) {
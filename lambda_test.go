@@ -3,6 +3,7 @@ package gmnlisp
 import (
 	"context"
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -28,3 +29,49 @@ func TestLambdaGo(t *testing.T) {
 		t.Fatal("Too Many argumenets error did not occur")
 	}
 }
+
+func TestBacktrace(t *testing.T) {
+	w := New()
+	_, err := w.Interpret(context.TODO(), `
+		(defun foo (x) (car x))
+		(defun bar (x y) (list (foo (+ x y))))
+		(bar 1 2)`)
+	var bt *Backtrace
+	if !errors.As(err, &bt) {
+		t.Fatalf("Backtrace was not returned: %v", err)
+	}
+	if len(bt.Frames) != 2 {
+		t.Fatalf("expected 2 frames, but %d", len(bt.Frames))
+	}
+	if s := bt.Frames[0].String(); s != "(foo 3) at 3:26" {
+		t.Fatalf("expected `(foo 3) at 3:26`, but `%s`", s)
+	}
+	if s := bt.Frames[1].String(); s != "(bar 1 2) at 4:3" {
+		t.Fatalf("expected `(bar 1 2) at 4:3`, but `%s`", s)
+	}
+	var domainError *DomainError
+	if !errors.As(err, &domainError) {
+		t.Fatalf("DomainError was not returned: %v", err)
+	}
+}
+
+func TestBacktraceTruncated(t *testing.T) {
+	w := New()
+	_, err := w.Interpret(context.TODO(), `
+		(defun deep (n) (if (= n 0) (car n) (+ 1 (deep (- n 1)))))
+		(deep 1000)`)
+	var bt *Backtrace
+	if !errors.As(err, &bt) {
+		t.Fatalf("Backtrace was not returned: %v", err)
+	}
+	if len(bt.Frames) != 1001 {
+		t.Fatalf("expected 1001 frames, but %d", len(bt.Frames))
+	}
+	message := err.Error()
+	if n := strings.Count(message, "called from"); n != 2*backtraceEnds {
+		t.Fatalf("expected %d frames printed, but %d", 2*backtraceEnds, n)
+	}
+	if !strings.Contains(message, "... 981 more frames") {
+		t.Fatalf("the omitted frames were not reported: %s", message)
+	}
+}
@@ -0,0 +1,189 @@
+package gmnlisp
+
+import (
+	"context"
+	"io"
+)
+
+// Limits are the quotas of the resources which a World can use.
+// The zero value of each field means no limit. When one of them is
+// exceeded, StorageExhausted is raised. Steps, Conses, Strings and
+// Output are counted since SetLimits is called, so that once exceeded,
+// the World can not evaluate anything until SetLimits is called again.
+type Limits struct {
+	// Steps is the number of the function calls to be evaluated.
+	Steps int64
+	// Depth is the depth of the nested function calls. Set it to avoid
+	// the stack overflow of Go by the deep recursion.
+	Depth int
+	// Conses is the number of the conses and the elements of the arrays
+	// made by the builtin functions such as list, append, reverse, mapcar,
+	// subseq, convert, vector and create-array. The conses made by the
+	// reader, quasiquote, the macros and the functions of the extensions
+	// are not counted.
+	Conses int64
+	// Strings is the total bytes of the strings made by the builtin
+	// functions such as string-append, create-string, subseq, convert and
+	// format. The strings made by the reader and the functions of the
+	// extensions are not counted.
+	Strings int64
+	// Output is the bytes written to the standard output and the error output.
+	Output int64
+}
+
+type _Usage struct {
+	steps   int64
+	depth   int
+	conses  int64
+	strings int64
+	output  int64
+}
+
+const (
+	limitSteps   = "steps"
+	limitDepth   = "depth"
+	limitConses  = "conses"
+	limitStrings = "strings"
+	limitOutput  = "output"
+)
+
+// SetLimits sets the quotas and resets the usage of them.
+func (w *World) SetLimits(limits Limits) {
+	w.limits = limits
+	w.limited = limits != (Limits{})
+	w.used = _Usage{}
+	w.stdout = w.limitOutput(w.stdout)
+	w.errout = w.limitOutput(w.errout)
+}
+
+// handlerDepth is the depth of the calls given to the handlers of
+// StorageExhausted raised by Limits.Depth.
+const handlerDepth = 100
+
+func (w *World) exhausted(ctx context.Context, resource string) error {
+	if resource == limitDepth {
+		w.limits.Depth += handlerDepth
+		defer func() { w.limits.Depth -= handlerDepth }()
+	}
+	_, err := callHandler[Node](ctx, w, false, StorageExhausted{Resource: resource})
+	return err
+}
+
+// enterEval counts a step and a depth of the function call.
+// leaveEval has to be called after it succeeds.
+func (w *World) enterEval(ctx context.Context) error {
+	w.used.steps++
+	w.used.depth++
+	if w.limits.Steps > 0 && w.used.steps > w.limits.Steps {
+		w.used.depth--
+		return w.exhausted(ctx, limitSteps)
+	}
+	if w.limits.Depth > 0 && w.used.depth > w.limits.Depth {
+		w.used.depth--
+		return w.exhausted(ctx, limitDepth)
+	}
+	if w.limits.Conses > 0 && w.used.conses > w.limits.Conses {
+		w.used.depth--
+		return w.exhausted(ctx, limitConses)
+	}
+	if w.limits.Strings > 0 && w.used.strings > w.limits.Strings {
+		w.used.depth--
+		return w.exhausted(ctx, limitStrings)
+	}
+	if w.limits.Output > 0 && w.used.output > w.limits.Output {
+		w.used.depth--
+		return w.exhausted(ctx, limitOutput)
+	}
+	return nil
+}
+
+func (w *World) leaveEval() {
+	w.used.depth--
+}
+
+// step counts a step which evaluates no form such as the instructions of
+// the special forms in the bytecode.
+func (w *World) step(ctx context.Context) error {
+	if err := w.enterEval(ctx); err != nil {
+		return err
+	}
+	w.leaveEval()
+	return nil
+}
+
+// useConses counts n conses which are going to be made.
+func (w *World) useConses(ctx context.Context, n int64) error {
+	if w == nil || w.limits.Conses <= 0 {
+		return nil
+	}
+	w.used.conses += n
+	if w.used.conses > w.limits.Conses {
+		return w.exhausted(ctx, limitConses)
+	}
+	return nil
+}
+
+// useStrings counts n bytes of the strings which are going to be made.
+func (w *World) useStrings(ctx context.Context, n int64) error {
+	if w == nil || w.limits.Strings <= 0 {
+		return nil
+	}
+	w.used.strings += n
+	if w.used.strings > w.limits.Strings {
+		return w.exhausted(ctx, limitStrings)
+	}
+	return nil
+}
+
+// newString counts the bytes of s and returns it as String.
+func (w *World) newString(ctx context.Context, s string) (Node, error) {
+	if err := w.useStrings(ctx, int64(len(s))); err != nil {
+		return nil, err
+	}
+	return String(s), nil
+}
+
+type writerNode interface {
+	io.Writer
+	Node
+}
+
+// _LimitedOutput counts the bytes written to the standard output or
+// the error output.
+type _LimitedOutput struct {
+	writerNode
+	w *World
+}
+
+func (o *_LimitedOutput) Write(p []byte) (int, error) {
+	o.w.used.output += int64(len(p))
+	if o.w.used.output > o.w.limits.Output {
+		// Write has no context of the caller, so the handlers are called
+		// without it.
+		return 0, o.w.exhausted(context.TODO(), limitOutput)
+	}
+	return o.writerNode.Write(p)
+}
+
+func (o *_LimitedOutput) Column() int {
+	if c, ok := o.writerNode.(interface{ Column() int }); ok {
+		return c.Column()
+	}
+	return 0
+}
+
+func (o *_LimitedOutput) Flush() {
+	if f, ok := o.writerNode.(interface{ Flush() }); ok {
+		f.Flush()
+	}
+}
+
+func (w *World) limitOutput(stream writerNode) writerNode {
+	if o, ok := stream.(*_LimitedOutput); ok {
+		stream = o.writerNode
+	}
+	if w.limits.Output <= 0 {
+		return stream
+	}
+	return &_LimitedOutput{writerNode: stream, w: w}
+}
@@ -0,0 +1,54 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestLimits(t *testing.T) {
+	expectExhausted := func(limits Limits, code, resource string) {
+		t.Helper()
+		w := New()
+		w.SetStdout(io.Discard)
+		w.SetLimits(limits)
+		_, err := w.Interpret(context.TODO(), code)
+		var e StorageExhausted
+		if !errors.As(err, &e) {
+			t.Fatalf("%s: StorageExhausted was not raised: %v", code, err)
+		}
+		if e.Resource != resource {
+			t.Fatalf("%s: expect %s but %s", code, resource, e.Resource)
+		}
+	}
+	const deep = `(defun f (n) (if (= n 0) 0 (+ 1 (f (- n 1))))) (f 1000000)`
+	expectExhausted(Limits{Depth: 1000}, deep, "depth")
+	expectExhausted(Limits{Steps: 10000}, `(while t (+ 1 1))`, "steps")
+	expectExhausted(Limits{Steps: 10000}, `(defun f () (let ((x 0)) (while t (setq x x)))) (compile 'f) (f)`, "steps")
+	expectExhausted(Limits{Conses: 1000}, `(create-list 100000 0)`, "conses")
+	expectExhausted(Limits{Conses: 1000}, `(let ((x nil)) (while t (setq x (cons 1 x))))`, "conses")
+	expectExhausted(Limits{Strings: 1000}, `(create-string 100000)`, "strings")
+	expectExhausted(Limits{Strings: 1000}, `(let ((s "")) (while t (setq s (string-append s "a"))))`, "strings")
+	expectExhausted(Limits{Output: 100}, `(while t (format t "hello"))`, "output")
+	expectExhausted(Limits{Conses: 1000}, `(let ((x '(1 2 3))) (dotimes (i 5000) (reverse x)))`, "conses")
+	expectExhausted(Limits{Conses: 1000}, `(create-array '(10000000) 0)`, "conses")
+	expectExhausted(Limits{Conses: 1000}, `(dotimes (i 5000) (convert (vector 1 2) <list>))`, "conses")
+	expectExhausted(Limits{Strings: 1000}, `(dotimes (i 1000) (convert 'abcdef <string>))`, "strings")
+	expectExhausted(Limits{Strings: 1000}, `(dotimes (i 1000) (convert i <string>))`, "strings")
+
+	// the handlers can catch the condition by the depth
+	w := New()
+	w.SetLimits(Limits{Depth: 1000})
+	if e := w.Assert(`(progn `+deep[:len(deep)-len(` (f 1000000)`)]+`
+		(catch 'c
+			(with-handler
+				(lambda (c) (throw 'c (class-of c)))
+				(f 1000000))))`, storageExhaustedClass); e != "" {
+		t.Fatal(e)
+	}
+	// within the limits
+	if e := w.Assert(`(f 100)`, Integer(100)); e != "" {
+		t.Fatal(e)
+	}
+}
@@ -23,7 +23,10 @@ func funGetCdr(ctx context.Context, w *World, arg Node) (Node, error) {
 }
 
 // funList implements (list A B ...)
-func funList(_ context.Context, _ *World, list []Node) (Node, error) {
+func funList(ctx context.Context, w *World, list []Node) (Node, error) {
+	if err := w.useConses(ctx, int64(len(list))); err != nil {
+		return nil, err
+	}
 	var cons Node = Null
 	for i := len(list) - 1; i >= 0; i-- {
 		cons = &Cons{
@@ -99,7 +102,10 @@ func funAppend(ctx context.Context, w *World, list []Node) (Node, error) {
 }
 
 // funCons implements (cons CAR CDR)
-func funCons(_ context.Context, _ *World, first, second Node) (Node, error) {
+func funCons(ctx context.Context, w *World, first, second Node) (Node, error) {
+	if err := w.useConses(ctx, 1); err != nil {
+		return nil, err
+	}
 	return &Cons{Car: first, Cdr: second}, nil
 }
 
@@ -161,6 +167,9 @@ func funCreateList(ctx context.Context, w *World, argv []Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
+	if err := w.useConses(ctx, int64(n)); err != nil {
+		return nil, err
+	}
 	result := Null
 	for ; n > 0; n-- {
 		result = &Cons{
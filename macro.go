@@ -11,6 +11,7 @@ import (
 const macro_trace = false
 
 type _Macro struct {
+	name    Symbol
 	param   []Symbol
 	code    Node
 	rest    Symbol
@@ -93,6 +94,22 @@ func expandJoinedForm(n Node) Node {
 }
 
 func (m *_Macro) expand(ctx context.Context, w *World, n Node) (Node, error) {
+	var args []Node
+	for p := n; IsSome(p); {
+		var arg Node
+		var err error
+		arg, p, err = Shift(p)
+		if err != nil {
+			break
+		}
+		args = append(args, arg)
+	}
+	depth := w.enterFrame(m.name, args, w.callSite)
+	newCode, err := m.expandSub(ctx, w, n)
+	return newCode, w.leaveFrame(depth, err)
+}
+
+func (m *_Macro) expandSub(ctx context.Context, w *World, n Node) (Node, error) {
 	var err error
 
 	lexical := Variables{}
@@ -156,15 +173,10 @@ func lambdaMacro(ctx context.Context, w *World, n Node) (*_Macro, error) {
 	if err != nil {
 		return nil, err
 	}
-	code := p.code
-	if c, err := expandMacroInList(ctx, w, code); err == nil {
-		code = c
-	} else {
-		println(err.Error())
-	}
 	return &_Macro{
+		name:    nulSymbol,
 		param:   p.param,
-		code:    code,
+		code:    p.code,
 		rest:    p.rest,
 		lexical: w,
 	}, nil
@@ -183,7 +195,8 @@ func cmdDefMacro(ctx context.Context, w *World, n Node) (Node, error) {
 	if err != nil {
 		return nil, err
 	}
-	w.defun.Set(macroName, value)
+	value.name = macroName
+	w.defineFunc(macroName, value)
 	if w.macro == nil {
 		w.macro = make(map[Symbol]*_Macro)
 	}
@@ -220,3 +233,255 @@ func funMacroExpand(ctx context.Context, w *World, arg Node) (Node, error) {
 	}
 	return m.expand(ctx, w, param)
 }
+
+// macroOf returns the macro which f is or nil.
+func macroOf(ctx context.Context, w *World, f Callable) *_Macro {
+	switch v := f.(type) {
+	case *_Macro:
+		return v
+	case *LispString:
+		value, err := v.Eval(ctx, w)
+		if err != nil {
+			return nil
+		}
+		if ref, ok := value.(FunctionRef); ok {
+			if m, ok := ref.value.(*_Macro); ok {
+				return m
+			}
+		}
+	}
+	return nil
+}
+
+// mapForms calls f with each element of the list and makes the list of
+// the results. The first skip elements and the dotted tail are kept.
+func mapForms(list Node, skip int, f func(Node) (Node, error)) (Node, error) {
+	cons, ok := list.(*Cons)
+	if !ok {
+		return list, nil
+	}
+	car := cons.Car
+	if skip <= 0 {
+		var err error
+		if car, err = f(car); err != nil {
+			return nil, err
+		}
+	}
+	cdr, err := mapForms(cons.Cdr, skip-1, f)
+	if err != nil {
+		return nil, err
+	}
+	return &Cons{Car: car, Cdr: cdr}, nil
+}
+
+// expandAllForms expands the macros in the forms after the first skip
+// elements of list.
+func expandAllForms(ctx context.Context, w *World, list Node, skip int) (Node, error) {
+	return mapForms(list, skip, func(form Node) (Node, error) {
+		return expandAll(ctx, w, form)
+	})
+}
+
+// expandAllClauses expands the forms after the first skip elements of
+// each clause of list such as the bindings of let or the clauses of cond.
+func expandAllClauses(ctx context.Context, w *World, list Node, skip int) (Node, error) {
+	return mapForms(list, 0, func(clause Node) (Node, error) {
+		return expandAllForms(ctx, w, clause, skip)
+	})
+}
+
+// localFunction is bound to the names of flet and labels while expandAll
+// walks their bodies. It is never called.
+var localFunction = &Function{}
+
+// specialExpanders expand the subforms of the special forms whose
+// arguments are not all forms.
+var specialExpanders map[uintptr]func(context.Context, *World, Node) (Node, error)
+
+func init() {
+	keep := func(_ context.Context, _ *World, args Node) (Node, error) {
+		return args, nil
+	}
+	bindings := func(ctx context.Context, w *World, args Node) (Node, error) {
+		cons, ok := args.(*Cons)
+		if !ok {
+			return args, nil
+		}
+		vars, err := expandAllClauses(ctx, w, cons.Car, 1)
+		if err != nil {
+			return nil, err
+		}
+		body, err := expandAllForms(ctx, w, cons.Cdr, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &Cons{Car: vars, Cdr: body}, nil
+	}
+	// functions expands flet or labels. The names of the local functions
+	// are bound while the body is expanded so that they are not taken for
+	// the global macros. They are also bound in the definitions of labels.
+	functions := func(recursive bool) func(context.Context, *World, Node) (Node, error) {
+		return func(ctx context.Context, w *World, args Node) (Node, error) {
+			cons, ok := args.(*Cons)
+			if !ok {
+				return args, nil
+			}
+			local := Functions{}
+			for list := cons.Car; ; {
+				def, ok := list.(*Cons)
+				if !ok {
+					break
+				}
+				if clause, ok := def.Car.(*Cons); ok {
+					if name, ok := clause.Car.(Symbol); ok {
+						local[name] = localFunction
+					}
+				}
+				list = def.Cdr
+			}
+			nw := w.Flet(local)
+			defs := w
+			if recursive {
+				defs = nw
+			}
+			funcs, err := expandAllClauses(ctx, defs, cons.Car, 2)
+			if err != nil {
+				return nil, err
+			}
+			body, err := expandAllForms(ctx, nw, cons.Cdr, 0)
+			if err != nil {
+				return nil, err
+			}
+			return &Cons{Car: funcs, Cdr: body}, nil
+		}
+	}
+	// withOpenFile expands with-open-input-file and the like whose first
+	// argument is (name filename element-class).
+	withOpenFile := func(ctx context.Context, w *World, args Node) (Node, error) {
+		cons, ok := args.(*Cons)
+		if !ok {
+			return args, nil
+		}
+		spec, err := expandAllForms(ctx, w, cons.Car, 1)
+		if err != nil {
+			return nil, err
+		}
+		body, err := expandAllForms(ctx, w, cons.Cdr, 0)
+		if err != nil {
+			return nil, err
+		}
+		return &Cons{Car: spec, Cdr: body}, nil
+	}
+	skip := func(n int) func(context.Context, *World, Node) (Node, error) {
+		return func(ctx context.Context, w *World, args Node) (Node, error) {
+			return expandAllForms(ctx, w, args, n)
+		}
+	}
+	specialExpanders = map[uintptr]func(context.Context, *World, Node) (Node, error){
+		specialKey(cmdQuote):              keep,
+		specialKey(cmdFunction):           keep,
+		specialKey(cmdQuasiQuote):         keep,
+		specialKey(cmdLet):                bindings,
+		specialKey(cmdLetX):               bindings,
+		specialKey(cmdDynamicLet):         bindings,
+		specialKey(cmdFlet):               functions(false),
+		specialKey(cmdLabels):             functions(true),
+		specialKey(cmdLambda):             skip(1),
+		specialKey(cmdDefun):              skip(2),
+		specialKey(cmdDefMacro):           skip(2),
+		specialKey(cmdLambdaMacro):        skip(1),
+		specialKey(cmdDefClass):           keep,
+		specialKey(cmdDefGeneric):         keep,
+		specialKey(cmdExpandDefun):        keep,
+		specialKey(cmdTrace):              keep,
+		specialKey(cmdWithOpenInputFile):  withOpenFile,
+		specialKey(cmdWithOpenOutputFile): withOpenFile,
+		specialKey(cmdWithOpenIoFile):     withOpenFile,
+		specialKey(cmdDefMethod): func(ctx context.Context, w *World, args Node) (Node, error) {
+			// (defmethod name qualifier* parameters form*)
+			skip := 1
+			for list := args; ; skip++ {
+				cons, ok := list.(*Cons)
+				if !ok {
+					return args, nil
+				}
+				if _, ok := cons.Car.(*Cons); skip > 1 && (ok || IsNull(cons.Car)) {
+					break
+				}
+				list = cons.Cdr
+			}
+			return expandAllForms(ctx, w, args, skip)
+		},
+		specialKey(cmdRestartCase): func(ctx context.Context, w *World, args Node) (Node, error) {
+			cons, ok := args.(*Cons)
+			if !ok {
+				return args, nil
+			}
+			form, err := expandAll(ctx, w, cons.Car)
+			if err != nil {
+				return nil, err
+			}
+			clauses, err := expandAllClauses(ctx, w, cons.Cdr, 2)
+			if err != nil {
+				return nil, err
+			}
+			return &Cons{Car: form, Cdr: clauses}, nil
+		},
+		specialKey(cmdCond): func(ctx context.Context, w *World, args Node) (Node, error) {
+			return expandAllClauses(ctx, w, args, 0)
+		},
+		specialKey(cmdCase): func(ctx context.Context, w *World, args Node) (Node, error) {
+			cons, ok := args.(*Cons)
+			if !ok {
+				return args, nil
+			}
+			key, err := expandAll(ctx, w, cons.Car)
+			if err != nil {
+				return nil, err
+			}
+			clauses, err := expandAllClauses(ctx, w, cons.Cdr, 1)
+			if err != nil {
+				return nil, err
+			}
+			return &Cons{Car: key, Cdr: clauses}, nil
+		},
+	}
+}
+
+// expandAll expands the macros in form and its subforms.
+func expandAll(ctx context.Context, w *World, form Node) (Node, error) {
+	for {
+		cons, ok := form.(*Cons)
+		if !ok {
+			return form, nil
+		}
+		symbol, ok := cons.Car.(Symbol)
+		if !ok {
+			return expandAllForms(ctx, w, cons, 0)
+		}
+		f, err := w.GetFunc(symbol)
+		if err != nil {
+			return expandAllForms(ctx, w, cons, 1)
+		}
+		if m := macroOf(ctx, w, f); m != nil {
+			if form, err = m.expand(ctx, w, cons.Cdr); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if sf, ok := f.(SpecialF); ok {
+			if expand, ok := specialExpanders[specialKey(sf)]; ok {
+				args, err := expand(ctx, w, cons.Cdr)
+				if err != nil {
+					return nil, err
+				}
+				return &Cons{Car: symbol, Cdr: args}, nil
+			}
+		}
+		return expandAllForms(ctx, w, cons, 1)
+	}
+}
+
+func funMacroExpandAll(ctx context.Context, w *World, form Node) (Node, error) {
+	return expandAll(ctx, w, form)
+}
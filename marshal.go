@@ -0,0 +1,589 @@
+package gmnlisp
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+var (
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+	nodeType  = reflect.TypeOf((*Node)(nil)).Elem()
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+var (
+	errNotConvertible = errors.New("can not convert to Node")
+	errNotPointer     = errors.New("not a non-nil pointer")
+	errCyclicValue    = errors.New("cyclic value")
+)
+
+// ToNode converts the Go value v to Node.
+//
+//   - string, integers, floats and bool are converted to String, Integer,
+//     Float and t or nil.
+//   - slices are converted to lists and arrays to vectors.
+//   - maps are converted to hash tables.
+//   - structs are converted to the instances of the classes whose slots
+//     are their exported fields. The name of the slot is given by the tag
+//     `lisp:"name"` or made from the field name as MaxRetries to
+//     max-retries. The fields tagged with `lisp:"-"` are ignored.
+//   - time.Time is converted to the string formatted with time.RFC3339Nano.
+//   - pointers are converted to the values they point and nil to nil.
+//     An error is returned when a pointer, a map or a slice refers to itself.
+//   - Node is not converted.
+func ToNode(v any) (Node, error) {
+	if v == nil {
+		return Null, nil
+	}
+	return toNode(reflect.ValueOf(v))
+}
+
+// _Visit is a pointer, a map or a slice being converted by toNode. The
+// type is needed because a struct and its first field have the same
+// address.
+type _Visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// FromNode stores the value converted from node into the value pointed by
+// ptr. It is the reverse of ToNode. When the type pointed by ptr is an
+// empty interface, String, Integer, Float, Rune, lists, vectors, hash
+// tables and instances are converted to string, int64, float64, rune,
+// []any, []any, map[any]any and map[string]any. DomainError is returned
+// when node does not match the type.
+func FromNode(node Node, ptr any) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("gmnlisp.FromNode: %T: %w", ptr, errNotPointer)
+	}
+	return fromNode(node, v.Elem())
+}
+
+func toNode(v reflect.Value) (Node, error) {
+	return toNodeIn(v, nil)
+}
+
+// toNodeIn converts v as toNode. path has the pointers, the maps and the slices which
+// contain v, so that the cyclic value is not converted forever.
+func toNodeIn(v reflect.Value, path map[_Visit]struct{}) (Node, error) {
+	if v.Type().Implements(nodeType) {
+		if (v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer) && v.IsNil() {
+			return Null, nil
+		}
+		return v.Interface().(Node), nil
+	}
+	if v.Type() == timeType {
+		return String(v.Interface().(time.Time).Format(time.RFC3339Nano)), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return String(v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return True, nil
+		}
+		return Null, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return Integer(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if n := v.Uint(); n > math.MaxInt64 {
+			return BigInt{Int: new(big.Int).SetUint64(n)}, nil
+		}
+		return Integer(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return Float(v.Float()), nil
+	case reflect.Slice:
+		if v.Len() > 0 {
+			var err error
+			if path, err = enter(path, v); err != nil {
+				return nil, err
+			}
+			defer delete(path, _Visit{ptr: v.Pointer(), typ: v.Type()})
+		}
+		var list Node = Null
+		for i := v.Len() - 1; i >= 0; i-- {
+			value, err := toNodeIn(v.Index(i), path)
+			if err != nil {
+				return nil, err
+			}
+			list = &Cons{Car: value, Cdr: list}
+		}
+		return list, nil
+	case reflect.Array:
+		vector := &Array{list: make([]Node, v.Len()), dim: []int{v.Len()}}
+		for i := range vector.list {
+			value, err := toNodeIn(v.Index(i), path)
+			if err != nil {
+				return nil, err
+			}
+			vector.list[i] = value
+		}
+		return vector, nil
+	case reflect.Map:
+		path, err := enter(path, v)
+		if err != nil {
+			return nil, err
+		}
+		defer delete(path, _Visit{ptr: v.Pointer(), typ: v.Type()})
+		hash := make(_Hash, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toNodeIn(iter.Key(), path)
+			if err != nil {
+				return nil, err
+			}
+			if !canUseHashKey(key) {
+				return nil, fmt.Errorf("%w: %s", ErrNotSupportType, iter.Key().Type())
+			}
+			value, err := toNodeIn(iter.Value(), path)
+			if err != nil {
+				return nil, err
+			}
+			hash[key] = value
+		}
+		return hash, nil
+	case reflect.Struct:
+		s := structOf(v.Type())
+		obj := &_StandardObject{
+			_StandardClass: s.class,
+			Slot:           make(map[Symbol]Node, len(s.fields)),
+		}
+		for _, f := range s.fields {
+			field, err := v.FieldByIndexErr(f.index)
+			if err != nil {
+				// the field is promoted through the nil embedded pointer.
+				continue
+			}
+			value, err := toNodeIn(field, path)
+			if err != nil {
+				return nil, err
+			}
+			obj.Slot[f.name] = value
+		}
+		return obj, nil
+	case reflect.Pointer:
+		if v.IsNil() {
+			return Null, nil
+		}
+		path, err := enter(path, v)
+		if err != nil {
+			return nil, err
+		}
+		defer delete(path, _Visit{ptr: v.Pointer(), typ: v.Type()})
+		return toNodeIn(v.Elem(), path)
+	case reflect.Interface:
+		if v.IsNil() {
+			return Null, nil
+		}
+		return toNodeIn(v.Elem(), path)
+	}
+	return nil, fmt.Errorf("%w: %s", errNotConvertible, v.Type())
+}
+
+// enter adds the pointer, the map or the slice v to path. It returns an error when v
+// is in path already.
+func enter(path map[_Visit]struct{}, v reflect.Value) (map[_Visit]struct{}, error) {
+	key := _Visit{ptr: v.Pointer(), typ: v.Type()}
+	if _, ok := path[key]; ok {
+		return nil, fmt.Errorf("%w: %s", errCyclicValue, v.Type())
+	}
+	if path == nil {
+		path = make(map[_Visit]struct{})
+	}
+	path[key] = struct{}{}
+	return path, nil
+}
+
+// fromNode stores node converted into v. It returns *DomainError when
+// node does not match the type of v.
+func fromNode(node Node, v reflect.Value) error {
+	if u, ok := node.(Uneval); ok {
+		node = u.Node
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Interface && t.NumMethod() == 0 {
+		value, err := fromNodeToAny(node)
+		if err != nil {
+			return err
+		}
+		if value == nil {
+			v.Set(reflect.Zero(t))
+		} else {
+			v.Set(reflect.ValueOf(value))
+		}
+		return nil
+	}
+	if node != nil && reflect.TypeOf(node).AssignableTo(t) {
+		v.Set(reflect.ValueOf(node))
+		return nil
+	}
+	if t == timeType {
+		switch value := node.(type) {
+		case String:
+			tm, err := time.Parse(time.RFC3339Nano, string(value))
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(tm))
+			return nil
+		case Integer:
+			v.Set(reflect.ValueOf(time.Unix(int64(value), 0)))
+			return nil
+		}
+		return &DomainError{Object: node, ExpectedClass: stringClass}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		if s, ok := node.(String); ok {
+			v.SetString(string(s))
+			return nil
+		}
+		return &DomainError{Object: node, ExpectedClass: stringClass}
+	case reflect.Bool:
+		v.SetBool(IsSome(node))
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		var ok bool
+		switch value := node.(type) {
+		case Integer:
+			n, ok = int64(value), true
+		case BigInt:
+			n, ok = value.Int64(), value.IsInt64()
+		case Rune:
+			n, ok = int64(value), t.Kind() == reflect.Int32
+		}
+		if ok && !v.OverflowInt(n) {
+			v.SetInt(n)
+			return nil
+		}
+		return &DomainError{Object: node, ExpectedClass: integerClass}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		var n uint64
+		var ok bool
+		switch value := node.(type) {
+		case Integer:
+			n, ok = uint64(value), value >= 0
+		case BigInt:
+			n, ok = value.Uint64(), value.IsUint64()
+		}
+		if ok && !v.OverflowUint(n) {
+			v.SetUint(n)
+			return nil
+		}
+		return &DomainError{Object: node, ExpectedClass: integerClass}
+	case reflect.Float32, reflect.Float64:
+		switch value := node.(type) {
+		case Float:
+			v.SetFloat(float64(value))
+			return nil
+		case Integer:
+			v.SetFloat(float64(value))
+			return nil
+		}
+		return &DomainError{Object: node, ExpectedClass: floatClass}
+	case reflect.Slice:
+		if s, ok := node.(String); ok && t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(string(s)))
+			return nil
+		}
+		elements, ok := sequenceElements(node)
+		if !ok {
+			return &DomainError{Object: node, ExpectedClass: listClass}
+		}
+		if elements == nil {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		slice := reflect.MakeSlice(t, len(elements), len(elements))
+		for i, element := range elements {
+			if err := fromNode(element, slice.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(slice)
+		return nil
+	case reflect.Array:
+		elements, ok := sequenceElements(node)
+		if !ok || len(elements) != t.Len() {
+			return &DomainError{Object: node, ExpectedClass: arrayClass}
+		}
+		for i, element := range elements {
+			if err := fromNode(element, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Map:
+		hash, ok := node.(_Hash)
+		if !ok {
+			if IsNone(node) {
+				v.Set(reflect.Zero(t))
+				return nil
+			}
+			return &DomainError{Object: node, ExpectedClass: hashClass}
+		}
+		m := reflect.MakeMapWithSize(t, len(hash))
+		for key, value := range hash {
+			k := reflect.New(t.Key()).Elem()
+			if err := fromNode(key, k); err != nil {
+				return err
+			}
+			e := reflect.New(t.Elem()).Elem()
+			if err := fromNode(value, e); err != nil {
+				return err
+			}
+			m.SetMapIndex(k, e)
+		}
+		v.Set(m)
+		return nil
+	case reflect.Struct:
+		obj, ok := node.(*_StandardObject)
+		if !ok {
+			return &DomainError{Object: node, ExpectedClass: standardObjectClass}
+		}
+		for _, f := range structOf(t).fields {
+			value, ok := obj.Slot[f.name]
+			if !ok {
+				continue
+			}
+			field, err := fieldToSet(v, f.index)
+			if err != nil {
+				return err
+			}
+			if err := fromNode(value, field); err != nil {
+				return err
+			}
+		}
+		return nil
+	case reflect.Pointer:
+		if IsNone(node) {
+			v.Set(reflect.Zero(t))
+			return nil
+		}
+		p := reflect.New(t.Elem())
+		if err := fromNode(node, p.Elem()); err != nil {
+			return err
+		}
+		v.Set(p)
+		return nil
+	}
+	return &DomainError{Object: node, ExpectedClass: objectClass}
+}
+
+// fieldToSet returns the field of the struct v by index. The nil embedded
+// pointers on the way are set to the new structs.
+func fieldToSet(v reflect.Value, index []int) (reflect.Value, error) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return v, fmt.Errorf("%w: %s", errNotPointer, v.Type())
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, nil
+}
+
+// sequenceElements returns the elements of a list or a vector.
+// The second value is false when node is neither of them.
+func sequenceElements(node Node) ([]Node, bool) {
+	if a, ok := node.(*Array); ok && len(a.dim) == 1 {
+		return a.list, true
+	}
+	if IsNone(node) {
+		return nil, true
+	}
+	if _, ok := node.(*Cons); !ok {
+		return nil, false
+	}
+	var elements []Node
+	for IsSome(node) {
+		cons, ok := node.(*Cons)
+		if !ok {
+			return nil, false
+		}
+		elements = append(elements, cons.Car)
+		node = cons.Cdr
+	}
+	return elements, true
+}
+
+func fromNodeToAny(node Node) (any, error) {
+	switch value := node.(type) {
+	case nil, _NullType:
+		return nil, nil
+	case _TrueType:
+		return true, nil
+	case String:
+		return string(value), nil
+	case Integer:
+		return int64(value), nil
+	case Float:
+		return float64(value), nil
+	case Rune:
+		return rune(value), nil
+	case _Hash:
+		m := make(map[any]any, len(value))
+		for key, val := range value {
+			k, err := fromNodeToAny(key)
+			if err != nil {
+				return nil, err
+			}
+			if m[k], err = fromNodeToAny(val); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case *_StandardObject:
+		m := make(map[string]any, len(value.Slot))
+		for key, val := range value.Slot {
+			var err error
+			if m[key.String()], err = fromNodeToAny(val); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	}
+	if elements, ok := sequenceElements(node); ok {
+		list := make([]any, len(elements))
+		for i, element := range elements {
+			var err error
+			if list[i], err = fromNodeToAny(element); err != nil {
+				return nil, err
+			}
+		}
+		return list, nil
+	}
+	return node, nil
+}
+
+type structField struct {
+	name  Symbol
+	index []int
+}
+
+// _Struct is the class made from a Go struct type and the fields
+// corresponding to its slots.
+type _Struct struct {
+	class  *_StandardClass
+	fields []structField
+}
+
+var structs sync.Map // reflect.Type -> *_Struct
+
+// structOf returns the class for the struct type t. The same class is
+// returned for the same type. Each slot has the initarg of its name, the
+// accessor named as TYPE-SLOT and the initform of the zero value.
+func structOf(t reflect.Type) *_Struct {
+	if s, ok := structs.Load(t); ok {
+		return s.(*_Struct)
+	}
+	typeName := t.Name()
+	if typeName == "" {
+		typeName = "struct"
+	}
+	class := &_StandardClass{
+		serial: int(classCounter.Add(1)),
+		Symbol: NewSymbol("<" + lispName(typeName) + ">"),
+		Slot:   make(map[Symbol]*_SlotSpec),
+	}
+	s := &_Struct{class: class}
+	for _, f := range reflect.VisibleFields(t) {
+		if f.Anonymous || !f.IsExported() {
+			continue
+		}
+		tag := f.Tag.Get("lisp")
+		if tag == "-" {
+			continue
+		}
+		if tag == "" {
+			tag = lispName(f.Name)
+		}
+		name := NewSymbol(tag)
+		if _, ok := class.Slot[name]; ok {
+			continue
+		}
+		zero := reflect.Zero(f.Type)
+		class.Slot[name] = &_SlotSpec{
+			identifier: name,
+			initarg:    []Symbol{name},
+			accessor:   []Symbol{NewSymbol(lispName(typeName) + "-" + tag)},
+			initform:   func() (Node, error) { return toNode(zero) },
+		}
+		class.slotOrder = append(class.slotOrder, name)
+		s.fields = append(s.fields, structField{name: name, index: f.Index})
+	}
+	class.cpl, _ = classPrecedenceList(class)
+	class.slots = class.mergeSlots()
+	actual, _ := structs.LoadOrStore(t, s)
+	return actual.(*_Struct)
+}
+
+// DefineStruct defines the class made from the struct type of v, which may
+// be a pointer to the struct, as ToNode does. The class is bound to the
+// name such as <order> for the type Order, so that it can be given to
+// create and used as a specializer of defmethod. Its slots have the
+// initargs of their names, and the accessors such as order-id and
+// set-order-id are defined as the generic functions.
+func (w *World) DefineStruct(v any) (Class, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("gmnlisp.DefineStruct: %T: %w", v, ErrExpectedClass)
+	}
+	class := structOf(t).class
+	for _, spec := range class.slots {
+		getter := newGetter(class, spec.identifier)
+		setter := newSetter(class, spec.identifier)
+		for _, f := range spec.accessor {
+			if err := registerMethod(w, f, class, getter); err != nil {
+				return nil, err
+			}
+			if err := registerMethod(w, NewSymbol("set-"+f.String()), class, setter); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if err := registerMethod(w, symInitializeObject, class, &_Method{
+		restType: objectClass,
+		types:    []Class{class},
+		method:   defaultInitializeObject,
+	}); err != nil {
+		return nil, err
+	}
+	w.DefineGlobal(class.Symbol, class)
+	return class, nil
+}
+
+// lispName converts the Go name such as MaxRetries or URLPath to
+// the Lisp name such as max-retries or url-path.
+func lispName(name string) string {
+	runes := []rune(name)
+	var buffer strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				buffer.WriteByte('-')
+			}
+			r = unicode.ToLower(r)
+		}
+		buffer.WriteRune(r)
+	}
+	return buffer.String()
+}
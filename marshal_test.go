@@ -0,0 +1,218 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestToNodeAndFromNode(t *testing.T) {
+	type Server struct {
+		Host string
+		Port int
+	}
+	type Config struct {
+		Name       string
+		MaxRetries int `lisp:"retries"`
+		Ratio      float64
+		Enabled    bool
+		Tags       []string
+		Limits     map[string]int
+		Servers    []*Server
+		Started    time.Time
+		Point      [2]int
+		secret     string
+		Ignored    string `lisp:"-"`
+	}
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	config := Config{
+		Name:       "main",
+		MaxRetries: 3,
+		Ratio:      0.5,
+		Enabled:    true,
+		Tags:       []string{"a", "b"},
+		Limits:     map[string]int{"cpu": 2},
+		Servers:    []*Server{{Host: "localhost", Port: 80}},
+		Started:    started,
+		Point:      [2]int{1, 2},
+		secret:     "x",
+		Ignored:    "y",
+	}
+	node, err := ToNode(&config)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	w := New()
+	w.DefineGlobal(NewSymbol("config"), node)
+	for code, expect := range map[string]Node{
+		`(class-name (class-of config))`:                        NewSymbol("<config>"),
+		`(slot-value config 'name)`:                             String("main"),
+		`(slot-value config 'retries)`:                          Integer(3),
+		`(slot-value config 'enabled)`:                          True,
+		`(slot-value config 'tags)`:                             List(String("a"), String("b")),
+		`(gethash "cpu" (slot-value config 'limits))`:           Integer(2),
+		`(slot-value (car (slot-value config 'servers)) 'port)`: Integer(80),
+		`(slot-value config 'started)`:                          String("2024-01-02T03:04:05Z"),
+		`(aref (slot-value config 'point) 1)`:                   Integer(2),
+		`(mapcar #'car (class-slots (class-of config)))`: List(
+			NewSymbol("name"), NewSymbol("retries"), NewSymbol("ratio"),
+			NewSymbol("enabled"), NewSymbol("tags"), NewSymbol("limits"),
+			NewSymbol("servers"), NewSymbol("started"), NewSymbol("point")),
+	} {
+		if e := w.Assert(code, expect); e != "" {
+			t.Fatal(e)
+		}
+	}
+	result, err := w.Interpret(context.TODO(), `
+		(set-slot-value 4 config 'retries)
+		(set-slot-value '("c") config 'tags)
+		config`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var back Config
+	if err := FromNode(result, &back); err != nil {
+		t.Fatal(err.Error())
+	}
+	if back.Name != "main" || back.MaxRetries != 4 || back.Ratio != 0.5 ||
+		!back.Enabled || len(back.Tags) != 1 || back.Tags[0] != "c" ||
+		back.Limits["cpu"] != 2 || back.Servers[0].Port != 80 ||
+		!back.Started.Equal(started) || back.Point != [2]int{1, 2} {
+		t.Fatalf("%#v", back)
+	}
+
+	var value any
+	if err := FromNode(List(Integer(1), String("a"), Null), &value); err != nil {
+		t.Fatal(err.Error())
+	}
+	if list, ok := value.([]any); !ok || len(list) != 3 || list[0] != int64(1) || list[1] != "a" || list[2] != nil {
+		t.Fatalf("%#v", value)
+	}
+	var n int
+	var domainError *DomainError
+	if err := FromNode(String("1"), &n); !errors.As(err, &domainError) {
+		t.Fatalf("DomainError was not returned: %v", err)
+	}
+}
+
+func TestToNodeEmbeddedAndCyclic(t *testing.T) {
+	type Base struct {
+		ID int
+	}
+	type Item struct {
+		*Base
+		Name string
+	}
+	node, err := ToNode(Item{Name: "a"})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, ok := node.(*_StandardObject).Slot[NewSymbol("id")]; ok {
+		t.Fatal("the field through the nil pointer was converted")
+	}
+	var item Item
+	if err := FromNode(&_StandardObject{Slot: map[Symbol]Node{
+		NewSymbol("id"):   Integer(7),
+		NewSymbol("name"): String("b"),
+	}}, &item); err != nil {
+		t.Fatal(err.Error())
+	}
+	if item.Base == nil || item.ID != 7 || item.Name != "b" {
+		t.Fatalf("%#v", item)
+	}
+
+	type Link struct {
+		Next *Link
+	}
+	link := &Link{}
+	link.Next = link
+	if _, err := ToNode(link); !errors.Is(err, errCyclicValue) {
+		t.Fatalf("cyclic value: %v", err)
+	}
+	self := map[string]any{}
+	self["self"] = self
+	if _, err := ToNode(self); !errors.Is(err, errCyclicValue) {
+		t.Fatalf("cyclic map: %v", err)
+	}
+	list := []any{nil}
+	list[0] = list
+	if _, err := ToNode(list); !errors.Is(err, errCyclicValue) {
+		t.Fatalf("cyclic slice: %v", err)
+	}
+	shared := &Link{}
+	if _, err := ToNode([]*Link{shared, shared}); err != nil {
+		t.Fatalf("shared value: %v", err)
+	}
+
+	node, err = ToNode(uint64(math.MaxUint64))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if s := node.String(); s != "18446744073709551615" {
+		t.Fatalf("uint64: %s", s)
+	}
+	var u uint64
+	if err := FromNode(node, &u); err != nil || u != math.MaxUint64 {
+		t.Fatalf("uint64: %d %v", u, err)
+	}
+	var i int64
+	var domainError *DomainError
+	if err := FromNode(node, &i); !errors.As(err, &domainError) {
+		t.Fatalf("DomainError was not returned: %v", err)
+	}
+}
+
+type testOrder struct {
+	ID     int
+	Amount float64
+	Items  []string
+}
+
+func TestDefineStruct(t *testing.T) {
+	w := New()
+	class, err := w.DefineStruct(&testOrder{})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if class.Name() != NewSymbol("<test-order>") {
+		t.Fatalf("class name: %s", class.Name())
+	}
+	order, err := ToNode(testOrder{ID: 1, Amount: 2.5, Items: []string{"a"}})
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	w.DefineGlobal(NewSymbol("order"), order)
+	if e := w.Assert(`
+		(defgeneric describe (x))
+		(defmethod describe ((x <object>)) 'object)
+		(defmethod describe ((x <test-order>))
+			(list (test-order-id x) (test-order-amount x) (test-order-items x)))
+		(list (describe order) (describe 1) (instancep order (class <test-order>)))`,
+		List(List(Integer(1), Float(2.5), List(String("a"))), NewSymbol("object"), True)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`
+		(defmethod print-object ((x <test-order>) s) (format s "ORDER"))
+		(format nil "~a" (create (class <test-order>) 'id 1))`, String("ORDER")); e != "" {
+		t.Fatal(e)
+	}
+	created, err := w.Interpret(context.TODO(), `
+		(let ((o (create (class <test-order>) 'id 7)))
+			(set-test-order-items '("x" "y") o)
+			o)`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	var back testOrder
+	if err := FromNode(created, &back); err != nil {
+		t.Fatal(err.Error())
+	}
+	if back.ID != 7 || back.Amount != 0 || len(back.Items) != 2 || back.Items[1] != "y" {
+		t.Fatalf("%#v", back)
+	}
+	if _, err := w.DefineStruct(1); err == nil {
+		t.Fatal("DefineStruct accepted an integer")
+	}
+}
@@ -3,36 +3,61 @@ package gmnlisp
 import (
 	"context"
 	"math"
+	"math/big"
+	"math/bits"
 )
 
-func funMath1(fn func(n float64) float64) SpecialF {
-	return func(ctx context.Context, w *World, node Node) (Node, error) {
-		value, node, err := w.ShiftAndEvalCar(ctx, node)
-		if err != nil {
-			return nil, err
-		}
-		if IsSome(node) {
-			return nil, ErrTooManyArguments
-		}
-		if i, ok := value.(Integer); ok {
-			return Float(fn(float64(int(i)))), nil
-		}
-		f, err := ExpectClass[Float](ctx, w, value)
+func raiseFloatingPointError(ctx context.Context, w *World, class Class, op Callable, operands ...Node) (Node, error) {
+	return callHandler[Node](ctx, w, true, &ArithmeticError{
+		Operation: FunctionRef{value: op},
+		Operands:  List(operands...),
+		Class:     class,
+	})
+}
+
+var quotientOp *Function
+
+func init() {
+	quotientOp = &Function{Min: 2, F: funQuotient}
+}
+
+// checkFloat returns v as Float. It signals <floating-point-overflow> when v
+// is infinite, <floating-point-underflow> when v is zero although nonZero
+// is true, and <arithmetic-error> when v is not a number.
+func checkFloat(ctx context.Context, w *World, v float64, nonZero bool, op Callable, operands ...Node) (Node, error) {
+	if math.IsNaN(v) {
+		return raiseFloatingPointError(ctx, w, arithmeticErrorClass, op, operands...)
+	}
+	if math.IsInf(v, 0) {
+		return raiseFloatingPointError(ctx, w, floatingPointOverflowClass, op, operands...)
+	}
+	if nonZero && v == 0 {
+		return raiseFloatingPointError(ctx, w, floatingPointUnderflowClass, op, operands...)
+	}
+	return Float(v), nil
+}
+
+// floatFails returns true when checkFloat signals an error for v.
+func floatFails(v float64, nonZero bool) bool {
+	return math.IsNaN(v) || math.IsInf(v, 0) || (nonZero && v == 0)
+}
+
+func funMath1(fn func(n float64) float64) *Function {
+	f := &Function{C: 1}
+	f.F = func(ctx context.Context, w *World, args []Node) (Node, error) {
+		x, err := expectFloat64(ctx, w, args[0])
 		if err != nil {
 			return nil, err
 		}
-		return Float(fn(float64(f))), nil
+		return checkFloat(ctx, w, fn(x), x != 0, f, args[0])
 	}
+	return f
 }
 
 func funLog(ctx context.Context, w *World, x Node) (Node, error) {
-	var f float64
-	if i, ok := x.(Integer); ok {
-		f = float64(int(i))
-	} else if _f, err := ExpectClass[Float](ctx, w, x); err != nil {
+	f, err := expectFloat64(ctx, w, x)
+	if err != nil {
 		return nil, err
-	} else {
-		f = float64(_f)
 	}
 	if f <= 0 {
 		return callHandler[Node](ctx, w, true, &DomainError{
@@ -42,3 +67,248 @@ func funLog(ctx context.Context, w *World, x Node) (Node, error) {
 	}
 	return Float(math.Log(f)), nil
 }
+
+// funAtan2 implements (atan2 X1 X2). It returns the arc tangent of X1/X2
+// using the signs of both to determine the quadrant.
+func funAtan2(ctx context.Context, w *World, first, second Node) (Node, error) {
+	y, err := expectFloat64(ctx, w, first)
+	if err != nil {
+		return nil, err
+	}
+	x, err := expectFloat64(ctx, w, second)
+	if err != nil {
+		return nil, err
+	}
+	return Float(math.Atan2(y, x)), nil
+}
+
+// funFloat implements (float X). It returns X as a floating-point number.
+func funFloat(ctx context.Context, w *World, x Node) (Node, error) {
+	f, err := expectFloat64(ctx, w, x)
+	if err != nil {
+		return nil, err
+	}
+	return checkFloat(ctx, w, f, false, Function1(funFloat), x)
+}
+
+// funExpt implements (expt X1 X2). It returns X1 raised to the power X2.
+// The result is exact when X1 is an integer or a ratio and X2 is an integer.
+func funExpt(ctx context.Context, w *World, base, power Node) (Node, error) {
+	if _, ok := base.(Ratio); (ok || integerClass.InstanceP(base)) && integerClass.InstanceP(power) {
+		b, err := expectRat(ctx, w, base)
+		if err != nil {
+			return nil, err
+		}
+		p, err := expectBigInt(ctx, w, power)
+		if err != nil {
+			return nil, err
+		}
+		if p.Sign() < 0 && b.Sign() == 0 {
+			return raiseDivisionByZero(ctx, w, base, power)
+		}
+		abs := new(big.Int).Abs(p)
+		num := new(big.Int).Exp(b.Num(), abs, nil)
+		den := new(big.Int).Exp(b.Denom(), abs, nil)
+		if p.Sign() < 0 {
+			num, den = den, num
+		}
+		return ratioOf(new(big.Rat).SetFrac(num, den)), nil
+	}
+	x, err := expectFloat64(ctx, w, base)
+	if err != nil {
+		return nil, err
+	}
+	y, err := expectFloat64(ctx, w, power)
+	if err != nil {
+		return nil, err
+	}
+	if x == 0 && y < 0 {
+		return raiseDivisionByZero(ctx, w, base, power)
+	}
+	if (x < 0 && y != math.Trunc(y)) || (x == 0 && y == 0 && !integerClass.InstanceP(power)) {
+		return callHandler[Node](ctx, w, true, &DomainError{
+			Object:        power,
+			ExpectedClass: integerClass,
+		})
+	}
+	return checkFloat(ctx, w, math.Pow(x, y), x != 0, Function2(funExpt), base, power)
+}
+
+// quotient2 returns first divided by second. The result is an integer
+// when both are integers and the division is exact.
+func quotient2(ctx context.Context, w *World, first, second Node) (Node, error) {
+	if integerClass.InstanceP(first) && integerClass.InstanceP(second) {
+		x, err := expectBigInt(ctx, w, first)
+		if err != nil {
+			return nil, err
+		}
+		y, err := expectBigInt(ctx, w, second)
+		if err != nil {
+			return nil, err
+		}
+		if y.Sign() == 0 {
+			return raiseDivisionByZero(ctx, w, first, second)
+		}
+		q, m := new(big.Int).QuoRem(x, y, new(big.Int))
+		if m.Sign() == 0 {
+			return integerOf(q), nil
+		}
+		f, _ := new(big.Rat).SetFrac(x, y).Float64()
+		return checkFloat(ctx, w, f, true, quotientOp, first, second)
+	}
+	x, err := expectFloat64(ctx, w, first)
+	if err != nil {
+		return nil, err
+	}
+	y, err := expectFloat64(ctx, w, second)
+	if err != nil {
+		return nil, err
+	}
+	if y == 0 {
+		return raiseDivisionByZero(ctx, w, first, second)
+	}
+	return checkFloat(ctx, w, x/y, x != 0, quotientOp, first, second)
+}
+
+// funQuotient implements (quotient DIVIDEND DIVISOR+).
+func funQuotient(ctx context.Context, w *World, args []Node) (Node, error) {
+	result := args[0]
+	for _, divisor := range args[1:] {
+		var err error
+		result, err = quotient2(ctx, w, result, divisor)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// funReciprocal implements (reciprocal X). It returns (quotient 1 X).
+func funReciprocal(ctx context.Context, w *World, x Node) (Node, error) {
+	return quotient2(ctx, w, Integer(1), x)
+}
+
+// funIsqrt implements (isqrt Z). It returns the greatest integer less than
+// or equal to the exact square root of the non-negative integer Z.
+func funIsqrt(ctx context.Context, w *World, z Node) (Node, error) {
+	v, err := expectBigInt(ctx, w, z)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sign() < 0 {
+		return callHandler[Node](ctx, w, true, &DomainError{
+			Object:        z,
+			ExpectedClass: integerClass,
+		})
+	}
+	return integerOf(new(big.Int).Sqrt(v)), nil
+}
+
+// funSignum implements (signum X). It returns -1, 0 or 1 according to the
+// sign of X in the same class as X.
+func funSignum(ctx context.Context, w *World, x Node) (Node, error) {
+	switch v := x.(type) {
+	case Integer:
+		if v > 0 {
+			return Integer(1), nil
+		} else if v < 0 {
+			return Integer(-1), nil
+		}
+		return v, nil
+	case BigInt:
+		return Integer(v.Sign()), nil
+	case Ratio:
+		return Integer(v.Sign()), nil
+	}
+	f, err := ExpectClass[Float](ctx, w, x)
+	if err != nil {
+		return nil, err
+	}
+	if f == 0 || math.IsNaN(float64(f)) {
+		return f, nil
+	}
+	return Float(math.Copysign(1, float64(f))), nil
+}
+
+// funIntegerLength implements (integer-length Z). It returns the number of
+// bits needed to represent Z in two's complement without the sign bit.
+func funIntegerLength(ctx context.Context, w *World, z Node) (Node, error) {
+	if v, ok := z.(Integer); ok {
+		if v < 0 {
+			v = ^v
+		}
+		return Integer(bits.Len64(uint64(v))), nil
+	}
+	v, err := expectBigInt(ctx, w, z)
+	if err != nil {
+		return nil, err
+	}
+	if v.Sign() < 0 {
+		v = new(big.Int).Not(v)
+	}
+	return Integer(v.BitLen()), nil
+}
+
+// funAbs implements (abs X). It returns the absolute value of X.
+func funAbs(ctx context.Context, w *World, x Node) (Node, error) {
+	switch v := x.(type) {
+	case Integer:
+		if v >= 0 {
+			return v, nil
+		}
+		return Integer(0).Sub(ctx, w, v)
+	case BigInt:
+		return integerOf(new(big.Int).Abs(v.Int)), nil
+	case Ratio:
+		return Ratio{Rat: new(big.Rat).Abs(v.Rat)}, nil
+	}
+	f, err := ExpectClass[Float](ctx, w, x)
+	if err != nil {
+		return nil, err
+	}
+	return Float(math.Abs(float64(f))), nil
+}
+
+// funMax implements (max X+). It returns the greatest number of X.
+func funMax(ctx context.Context, w *World, args []Node) (Node, error) {
+	result, err := ExpectInterface[canLessThan](ctx, w, args[0], numberClass)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args[1:] {
+		value, err := ExpectInterface[canLessThan](ctx, w, arg, numberClass)
+		if err != nil {
+			return nil, err
+		}
+		less, err := result.LessThan(ctx, w, value)
+		if err != nil {
+			return nil, err
+		}
+		if less {
+			result = value
+		}
+	}
+	return result, nil
+}
+
+// funMin implements (min X+). It returns the least number of X.
+func funMin(ctx context.Context, w *World, args []Node) (Node, error) {
+	result, err := ExpectInterface[canLessThan](ctx, w, args[0], numberClass)
+	if err != nil {
+		return nil, err
+	}
+	for _, arg := range args[1:] {
+		value, err := ExpectInterface[canLessThan](ctx, w, arg, numberClass)
+		if err != nil {
+			return nil, err
+		}
+		less, err := value.LessThan(ctx, w, result)
+		if err != nil {
+			return nil, err
+		}
+		if less {
+			result = value
+		}
+	}
+	return result, nil
+}
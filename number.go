@@ -16,6 +16,12 @@ var numberClass = &_BuiltInClass{
 		if _, ok := n.(Float); ok {
 			return true
 		}
+		if _, ok := n.(BigInt); ok {
+			return true
+		}
+		if _, ok := n.(Ratio); ok {
+			return true
+		}
 		return false
 	},
 	create: func() Node {
@@ -28,7 +34,19 @@ var numberClass = &_BuiltInClass{
 
 type Integer int64
 
-var integerClass = registerNewBuiltInClass[Integer]("<integer>", numberClass)
+var integerClass = registerClass(&_BuiltInClass{
+	name: NewSymbol("<integer>"),
+	instanceP: func(n Node) bool {
+		if _, ok := n.(Integer); ok {
+			return true
+		}
+		_, ok := n.(BigInt)
+		return ok
+	},
+	create: func() Node {
+		return Integer(0)
+	},
+}, numberClass)
 
 func (i Integer) ClassOf() Class {
 	return integerClass
@@ -51,35 +69,71 @@ func (i Integer) Equals(n Node, m EqlMode) bool {
 	}
 }
 
+func (i Integer) toBigInt() BigInt {
+	return BigInt{Int: big.NewInt(int64(i))}
+}
+
+func (i Integer) toRatio() Ratio {
+	return Ratio{Rat: new(big.Rat).SetInt64(int64(i))}
+}
+
 func (i Integer) Add(ctx context.Context, w *World, n Node) (Node, error) {
 	if _n, ok := n.(Float); ok {
-		return Float(i) + _n, nil
+		return Float(i).Add(ctx, w, _n)
+	}
+	if _n, ok := n.(BigInt); ok {
+		return i.toBigInt().Add(ctx, w, _n)
+	}
+	if _n, ok := n.(Ratio); ok {
+		return i.toRatio().Add(ctx, w, _n)
 	}
 	_n, err := ExpectClass[Integer](ctx, w, n)
 	if err == nil {
-		return i + _n, nil
+		if sum := i + _n; (sum > i) == (_n > 0) {
+			return sum, nil
+		}
+		return i.toBigInt().Add(ctx, w, _n)
 	}
 	return nil, err
 }
 
 func (i Integer) Sub(ctx context.Context, w *World, n Node) (Node, error) {
 	if _n, ok := n.(Float); ok {
-		return Float(i) - _n, nil
+		return Float(i).Sub(ctx, w, _n)
+	}
+	if _n, ok := n.(BigInt); ok {
+		return i.toBigInt().Sub(ctx, w, _n)
+	}
+	if _n, ok := n.(Ratio); ok {
+		return i.toRatio().Sub(ctx, w, _n)
 	}
 	_n, err := ExpectClass[Integer](ctx, w, n)
 	if err == nil {
-		return i - _n, nil
+		if diff := i - _n; (diff < i) == (_n > 0) {
+			return diff, nil
+		}
+		return i.toBigInt().Sub(ctx, w, _n)
 	}
 	return nil, err
 }
 
 func (i Integer) Multi(ctx context.Context, w *World, n Node) (Node, error) {
 	if _n, ok := n.(Float); ok {
-		return Float(i) * _n, nil
+		return Float(i).Multi(ctx, w, _n)
+	}
+	if _n, ok := n.(BigInt); ok {
+		return i.toBigInt().Multi(ctx, w, _n)
+	}
+	if _n, ok := n.(Ratio); ok {
+		return i.toRatio().Multi(ctx, w, _n)
 	}
 	_n, err := ExpectClass[Integer](ctx, w, n)
 	if err == nil {
-		return i * _n, nil
+		// i * -1 overflows only when i is math.MinInt64
+		if prod := i * _n; i == 0 || (prod/i == _n && (i != -1 || _n != math.MinInt64)) {
+			return prod, nil
+		}
+		return i.toBigInt().Multi(ctx, w, _n)
 	}
 	return nil, err
 }
@@ -151,13 +205,25 @@ func (i Integer) Divide(ctx context.Context, w *World, n Node) (Node, error) {
 		if _n == 0 {
 			return raiseDivisionByZero(ctx, w, i, n)
 		}
-		return Float(i) / _n, nil
+		return Float(i).Divide(ctx, w, _n)
+	}
+	if _n, ok := n.(BigInt); ok {
+		return i.toBigInt().Divide(ctx, w, _n)
+	}
+	if _n, ok := n.(Ratio); ok {
+		return i.toRatio().Divide(ctx, w, _n)
 	}
 	_n, err := ExpectClass[Integer](ctx, w, n)
 	if err == nil {
 		if _n == 0 {
 			return raiseDivisionByZero(ctx, w, i, n)
 		}
+		if _n == -1 && i == math.MinInt64 {
+			return i.toBigInt().Divide(ctx, w, _n)
+		}
+		if i%_n != 0 {
+			return ratioOf(big.NewRat(int64(i), int64(_n))), nil
+		}
 		return i / _n, nil
 	}
 	return nil, err
@@ -167,6 +233,12 @@ func (i Integer) LessThan(ctx context.Context, w *World, n Node) (bool, error) {
 	if _n, ok := n.(Float); ok {
 		return Float(i) < _n, nil
 	}
+	if _n, ok := n.(BigInt); ok {
+		return i.toBigInt().Cmp(_n.Int) < 0, nil
+	}
+	if _n, ok := n.(Ratio); ok {
+		return i.toRatio().LessThan(ctx, w, _n)
+	}
 	_n, err := ExpectClass[Integer](ctx, w, n)
 	if err == nil {
 		return i < _n, nil
@@ -191,6 +263,12 @@ func (f Float) Equals(n Node, m EqlMode) bool {
 		if _n, ok := n.(Float); ok && f == _n {
 			return true
 		}
+		if _n, ok := n.(BigInt); ok {
+			return f == _n.Float()
+		}
+		if _n, ok := n.(Ratio); ok {
+			return f == _n.Float()
+		}
 		_n, ok := n.(Integer)
 		return ok && f == Float(_n)
 	} else {
@@ -199,60 +277,72 @@ func (f Float) Equals(n Node, m EqlMode) bool {
 	}
 }
 
-func (f Float) Add(ctx context.Context, w *World, n Node) (Node, error) {
-	if _n, ok := n.(Integer); ok {
-		return f + Float(_n), nil
+// The operations given to checkFloat are made once, so that the float
+// arithmetic does not allocate them unless it fails. They are set by init
+// because they refer to themselves through the functions.
+var floatAddOp, floatSubOp, floatMultiOp, floatDivideOp *Function
+
+func init() {
+	floatAddOp = &Function{F: funAdd}
+	floatSubOp = &Function{F: funSub}
+	floatMultiOp = &Function{F: funMulti}
+	floatDivideOp = &Function{F: funDevide}
+}
+
+// check returns v as checkFloat does for the operation op of f and n. It
+// makes the list of the operands only when v is an error.
+func (f Float) check(ctx context.Context, w *World, v float64, nonZero bool, op *Function, n Node) (Node, error) {
+	if !floatFails(v, nonZero) {
+		return Float(v), nil
 	}
-	_n, err := ExpectClass[Float](ctx, w, n)
-	if err == nil {
-		return f + _n, nil
+	return checkFloat(ctx, w, v, nonZero, op, f, n)
+}
+
+func (f Float) Add(ctx context.Context, w *World, n Node) (Node, error) {
+	_n, err := expectFloat64(ctx, w, n)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	return f.check(ctx, w, float64(f)+_n, false, floatAddOp, n)
 }
 
 func (f Float) Sub(ctx context.Context, w *World, n Node) (Node, error) {
-	if _n, ok := n.(Integer); ok {
-		return f - Float(_n), nil
-	}
-	_n, err := ExpectClass[Float](ctx, w, n)
-	if err == nil {
-		return f - _n, nil
+	_n, err := expectFloat64(ctx, w, n)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	return f.check(ctx, w, float64(f)-_n, false, floatSubOp, n)
 }
 
 func (f Float) Multi(ctx context.Context, w *World, n Node) (Node, error) {
-	if _n, ok := n.(Integer); ok {
-		return f * Float(_n), nil
-	}
-	_n, err := ExpectClass[Float](ctx, w, n)
-	if err == nil {
-		return f * _n, nil
+	_n, err := expectFloat64(ctx, w, n)
+	if err != nil {
+		return nil, err
 	}
-	return nil, err
+	return f.check(ctx, w, float64(f)*_n, f != 0 && _n != 0, floatMultiOp, n)
 }
 
 func (f Float) Divide(ctx context.Context, w *World, n Node) (Node, error) {
-	if _n, ok := n.(Integer); ok {
-		if _n == 0 {
-			return raiseDivisionByZero(ctx, w, f, n)
-		}
-		return f / Float(_n), nil
+	_n, err := expectFloat64(ctx, w, n)
+	if err != nil {
+		return nil, err
 	}
-	_n, err := ExpectClass[Float](ctx, w, n)
-	if err == nil {
-		if _n == 0 {
-			return raiseDivisionByZero(ctx, w, f, n)
-		}
-		return f / _n, nil
+	if _n == 0 {
+		return raiseDivisionByZero(ctx, w, f, n)
 	}
-	return nil, err
+	return f.check(ctx, w, float64(f)/_n, f != 0, floatDivideOp, n)
 }
 
 func (f Float) LessThan(ctx context.Context, w *World, n Node) (bool, error) {
 	if _n, ok := n.(Integer); ok {
 		return f < Float(_n), nil
 	}
+	if _n, ok := n.(BigInt); ok {
+		return f < _n.Float(), nil
+	}
+	if _n, ok := n.(Ratio); ok {
+		return f < _n.Float(), nil
+	}
 	_n, err := ExpectClass[Float](ctx, w, n)
 	if err == nil {
 		return f < _n, nil
@@ -260,6 +350,21 @@ func (f Float) LessThan(ctx context.Context, w *World, n Node) (bool, error) {
 	return false, err
 }
 
+// expectFloat64 returns the value of the number n as float64.
+func expectFloat64(ctx context.Context, w *World, n Node) (float64, error) {
+	if i, ok := n.(Integer); ok {
+		return float64(i), nil
+	}
+	if b, ok := n.(BigInt); ok {
+		return float64(b.Float()), nil
+	}
+	if r, ok := n.(Ratio); ok {
+		return float64(r.Float()), nil
+	}
+	f, err := ExpectClass[Float](ctx, w, n)
+	return float64(f), err
+}
+
 func funSqrt(ctx context.Context, w *World, arg Node) (Node, error) {
 	cast := func(f float64) Node {
 		return Float(f)
@@ -274,10 +379,10 @@ func funSqrt(ctx context.Context, w *World, arg Node) (Node, error) {
 			}
 			return Float(f)
 		}
-	} else if _f, err := ExpectClass[Float](ctx, w, arg); err != nil {
+	} else if _f, err := expectFloat64(ctx, w, arg); err != nil {
 		return nil, err
 	} else {
-		f = float64(_f)
+		f = _f
 	}
 	if f < 0 {
 		return callHandler[Node](ctx, w, true, &DomainError{
@@ -288,10 +393,32 @@ func funSqrt(ctx context.Context, w *World, arg Node) (Node, error) {
 	return cast(math.Sqrt(f)), nil
 }
 
+// BigInt is the integer out of the range of Integer.
+// The results of the arithmetic are demoted to Integer when they fit.
 type BigInt struct {
 	*big.Int
 }
 
+// integerOf returns v as Integer if it fits in int64, otherwise as BigInt.
+func integerOf(v *big.Int) Node {
+	if v.IsInt64() {
+		return Integer(v.Int64())
+	}
+	return BigInt{Int: v}
+}
+
+// expectBigInt returns the value of the Integer or the BigInt n.
+func expectBigInt(ctx context.Context, w *World, n Node) (*big.Int, error) {
+	if b, ok := n.(BigInt); ok {
+		return b.Int, nil
+	}
+	i, err := ExpectClass[Integer](ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return big.NewInt(int64(i)), nil
+}
+
 func (b BigInt) Equals(n Node, m EqlMode) bool {
 	if bi, ok := n.(BigInt); ok {
 		return b.Int.Cmp(bi.Int) == 0
@@ -300,9 +427,100 @@ func (b BigInt) Equals(n Node, m EqlMode) bool {
 		bi := big.NewInt(int64(_n))
 		return b.Int.Cmp(bi) == 0
 	}
+	if m == EQUALP {
+		if _n, ok := n.(Float); ok {
+			return b.Float() == _n
+		}
+	}
 	return false
 }
 
 func (b BigInt) ClassOf() Class {
 	return integerClass
 }
+
+func (b BigInt) toRatio() Ratio {
+	return Ratio{Rat: new(big.Rat).SetInt(b.Int)}
+}
+
+// Float returns the nearest Float value of b.
+func (b BigInt) Float() Float {
+	f, _ := new(big.Float).SetInt(b.Int).Float64()
+	return Float(f)
+}
+
+func (b BigInt) Add(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Ratio); ok {
+		return b.toRatio().Add(ctx, w, _n)
+	}
+	if _n, ok := n.(Float); ok {
+		return b.Float().Add(ctx, w, _n)
+	}
+	_n, err := expectBigInt(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return integerOf(new(big.Int).Add(b.Int, _n)), nil
+}
+
+func (b BigInt) Sub(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Ratio); ok {
+		return b.toRatio().Sub(ctx, w, _n)
+	}
+	if _n, ok := n.(Float); ok {
+		return b.Float().Sub(ctx, w, _n)
+	}
+	_n, err := expectBigInt(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return integerOf(new(big.Int).Sub(b.Int, _n)), nil
+}
+
+func (b BigInt) Multi(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Ratio); ok {
+		return b.toRatio().Multi(ctx, w, _n)
+	}
+	if _n, ok := n.(Float); ok {
+		return b.Float().Multi(ctx, w, _n)
+	}
+	_n, err := expectBigInt(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return integerOf(new(big.Int).Mul(b.Int, _n)), nil
+}
+
+func (b BigInt) Divide(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Ratio); ok {
+		return b.toRatio().Divide(ctx, w, _n)
+	}
+	if _n, ok := n.(Float); ok {
+		if _n == 0 {
+			return raiseDivisionByZero(ctx, w, b, n)
+		}
+		return b.Float().Divide(ctx, w, _n)
+	}
+	_n, err := expectBigInt(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	if _n.Sign() == 0 {
+		return raiseDivisionByZero(ctx, w, b, n)
+	}
+	return ratioOf(new(big.Rat).SetFrac(b.Int, _n)), nil
+}
+
+func (b BigInt) LessThan(ctx context.Context, w *World, n Node) (bool, error) {
+	if _n, ok := n.(Ratio); ok {
+		return b.toRatio().LessThan(ctx, w, _n)
+	}
+	if _n, ok := n.(Float); ok {
+		return b.Float() < _n, nil
+	}
+	_n, err := expectBigInt(ctx, w, n)
+	if err != nil {
+		return false, err
+	}
+	return b.Int.Cmp(_n) < 0, nil
+}
@@ -3,6 +3,7 @@ package gmnlisp
 import (
 	"context"
 	"math"
+	"math/big"
 )
 
 func notNullToTrue(v Node, err error) (Node, error) {
@@ -31,14 +32,19 @@ func funAdd(ctx context.Context, w *World, args []Node) (Node, error) {
 		}
 	}
 	return inject(args, func(left, right Node) (Node, error) {
-		_left, err := ExpectInterface[canPlus](ctx, w, left, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		return _left.Add(ctx, w, right)
+		return plus(ctx, w, left, right)
 	})
 }
 
+// plus is (+ left right).
+func plus(ctx context.Context, w *World, left, right Node) (Node, error) {
+	_left, err := ExpectInterface[canPlus](ctx, w, left, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	return _left.Add(ctx, w, right)
+}
+
 type canMinus interface {
 	Node
 	Sub(context.Context, *World, Node) (Node, error)
@@ -55,44 +61,62 @@ func funSub(ctx context.Context, w *World, args []Node) (Node, error) {
 		return z.Sub(ctx, w, args[0])
 	}
 	return inject(args, func(left, right Node) (Node, error) {
-		_left, err := ExpectInterface[canMinus](ctx, w, left, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		return _left.Sub(ctx, w, right)
+		return minus(ctx, w, left, right)
 	})
 }
 
-func funMulti(ctx context.Context, w *World, args []Node) (Node, error) {
-	type CanMulti interface {
-		Node
-		Multi(context.Context, *World, Node) (Node, error)
+// minus is (- left right).
+func minus(ctx context.Context, w *World, left, right Node) (Node, error) {
+	_left, err := ExpectInterface[canMinus](ctx, w, left, floatClass)
+	if err != nil {
+		return nil, err
 	}
+	return _left.Sub(ctx, w, right)
+}
+
+type canMulti interface {
+	Node
+	Multi(context.Context, *World, Node) (Node, error)
+}
+
+func funMulti(ctx context.Context, w *World, args []Node) (Node, error) {
 	if len(args) == 1 {
-		_, err := ExpectInterface[CanMulti](ctx, w, args[0], floatClass)
+		_, err := ExpectInterface[canMulti](ctx, w, args[0], floatClass)
 		if err != nil {
 			return nil, err
 		}
 	}
 	return inject(args, func(left, right Node) (Node, error) {
-		_left, err := ExpectInterface[CanMulti](ctx, w, left, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		return _left.Multi(ctx, w, right)
+		return times(ctx, w, left, right)
 	})
 }
 
+// times is (* left right).
+func times(ctx context.Context, w *World, left, right Node) (Node, error) {
+	_left, err := ExpectInterface[canMulti](ctx, w, left, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	return _left.Multi(ctx, w, right)
+}
+
+// funDevide implements (/ X Y*). The division of integers is exact and
+// may result in a ratio. (/ X) returns the reciprocal of X.
 func funDevide(ctx context.Context, w *World, args []Node) (Node, error) {
 	type CanDevide interface {
 		Node
 		Divide(context.Context, *World, Node) (Node, error)
 	}
-	_left, err := ExpectInterface[CanDevide](ctx, w, args[0], floatClass)
-	if err != nil {
-		return nil, err
+	if len(args) == 1 {
+		return Integer(1).Divide(ctx, w, args[0])
 	}
-	return _left.Divide(ctx, w, args[1])
+	return inject(args, func(left, right Node) (Node, error) {
+		_left, err := ExpectInterface[CanDevide](ctx, w, left, floatClass)
+		if err != nil {
+			return nil, err
+		}
+		return _left.Divide(ctx, w, right)
+	})
 }
 
 type canLessThan interface {
@@ -102,85 +126,110 @@ type canLessThan interface {
 
 func funLessThan(ctx context.Context, w *World, args []Node) (Node, error) {
 	return notNullToTrue(inject(args, func(left, right Node) (Node, error) {
-		_left, err := ExpectInterface[canLessThan](ctx, w, left, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		result, err := _left.LessThan(ctx, w, right)
-		if err != nil {
-			return Null, err
-		}
-		if result {
-			return right, nil
-		}
-		return Null, nil
+		return lessThan(ctx, w, left, right)
 	}))
 }
 
+// lessThan returns right when left < right, otherwise nil.
+func lessThan(ctx context.Context, w *World, left, right Node) (Node, error) {
+	_left, err := ExpectInterface[canLessThan](ctx, w, left, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	result, err := _left.LessThan(ctx, w, right)
+	if err != nil {
+		return Null, err
+	}
+	if result {
+		return right, nil
+	}
+	return Null, nil
+}
+
 func funGreaterThan(ctx context.Context, w *World, args []Node) (Node, error) {
 	return notNullToTrue(inject(args, func(left, right Node) (Node, error) {
-		_right, err := ExpectInterface[canLessThan](ctx, w, right, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		result, err := _right.LessThan(ctx, w, left)
-		if err != nil {
-			return Null, err
-		}
-		if result {
-			return right, nil
-		}
-		return Null, nil
+		return greaterThan(ctx, w, left, right)
 	}))
 }
 
+// greaterThan returns right when left > right, otherwise nil.
+func greaterThan(ctx context.Context, w *World, left, right Node) (Node, error) {
+	_right, err := ExpectInterface[canLessThan](ctx, w, right, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	result, err := _right.LessThan(ctx, w, left)
+	if err != nil {
+		return Null, err
+	}
+	if result {
+		return right, nil
+	}
+	return Null, nil
+}
+
 func funEqualOp(ctx context.Context, w *World, args []Node) (Node, error) {
 	return notNullToTrue(inject(args, func(left, right Node) (Node, error) {
-		if left.Equals(right, EQUALP) {
-			return right, nil
-		}
-		return Null, nil
+		return numberEqual(ctx, w, left, right)
 	}))
 }
 
+// numberEqual returns right when left = right, otherwise nil.
+func numberEqual(ctx context.Context, w *World, left, right Node) (Node, error) {
+	if left.Equals(right, EQUALP) {
+		return right, nil
+	}
+	return Null, nil
+}
+
 func funGreaterOrEqual(ctx context.Context, w *World, args []Node) (Node, error) {
 	return notNullToTrue(inject(args, func(left, right Node) (Node, error) {
-		//     left >= right
-		// <=> not (left < right )
-		_left, err := ExpectInterface[canLessThan](ctx, w, left, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		result, err := _left.LessThan(ctx, w, right)
-		if err != nil {
-			return Null, err
-		}
-		if result {
-			return Null, nil
-		}
-		return right, nil
+		return greaterOrEqual(ctx, w, left, right)
 	}))
 }
 
+// greaterOrEqual returns right when left >= right, otherwise nil.
+func greaterOrEqual(ctx context.Context, w *World, left, right Node) (Node, error) {
+	//     left >= right
+	// <=> not (left < right )
+	_left, err := ExpectInterface[canLessThan](ctx, w, left, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	result, err := _left.LessThan(ctx, w, right)
+	if err != nil {
+		return Null, err
+	}
+	if result {
+		return Null, nil
+	}
+	return right, nil
+}
+
 func funLessOrEqual(ctx context.Context, w *World, args []Node) (Node, error) {
 	return notNullToTrue(inject(args, func(left, right Node) (Node, error) {
-		//     left <= right
-		// <=> not (right < left)
-		_right, err := ExpectInterface[canLessThan](ctx, w, right, floatClass)
-		if err != nil {
-			return nil, err
-		}
-		result, err := _right.LessThan(ctx, w, left)
-		if err != nil {
-			return Null, err
-		}
-		if result {
-			return Null, nil
-		}
-		return right, nil
+		return lessOrEqual(ctx, w, left, right)
 	}))
 }
 
+// lessOrEqual returns right when left <= right, otherwise nil.
+func lessOrEqual(ctx context.Context, w *World, left, right Node) (Node, error) {
+	//     left <= right
+	// <=> not (right < left)
+	_right, err := ExpectInterface[canLessThan](ctx, w, right, floatClass)
+	if err != nil {
+		return nil, err
+	}
+	result, err := _right.LessThan(ctx, w, left)
+	if err != nil {
+		return Null, err
+	}
+	if result {
+		return Null, nil
+	}
+	return right, nil
+}
+
 func cmdAnd(ctx context.Context, w *World, param Node) (Node, error) {
 	var value Node = True
 	for IsSome(param) {
@@ -215,81 +264,206 @@ func cmdOr(ctx context.Context, w *World, param Node) (Node, error) {
 	}
 }
 
-func floatToInteger(ctx context.Context, w *World, arg Node, f func(float64) float64) (Node, error) {
+func floatToInteger(ctx context.Context, w *World, arg Node, f func(float64) float64, r func(num, den *big.Int) *big.Int) (Node, error) {
 	if value, ok := arg.(Integer); ok {
 		return value, nil
 	}
+	if value, ok := arg.(BigInt); ok {
+		return value, nil
+	}
+	if value, ok := arg.(Ratio); ok {
+		return integerOf(r(value.Num(), value.Denom())), nil
+	}
 	value, err := ExpectClass[Float](ctx, w, arg)
 	if err != nil {
 		return nil, err
 	}
-	return Integer(int(f(float64(value)))), nil
+	v := f(float64(value))
+	if math.IsInf(v, 0) || math.IsNaN(v) {
+		return callHandler[Node](ctx, w, true, &DomainError{
+			Object:        arg,
+			ExpectedClass: floatClass,
+		})
+	}
+	if v >= math.MinInt64 && v < math.MaxInt64 {
+		return Integer(int64(v)), nil
+	}
+	b, _ := big.NewFloat(v).Int(nil)
+	return integerOf(b), nil
 }
 
 // funTruncate implements (truncte X). It returns the integer value of X.
 func funTruncate(ctx context.Context, w *World, arg Node) (Node, error) {
-	return floatToInteger(ctx, w, arg, math.Trunc)
+	return floatToInteger(ctx, w, arg, math.Trunc, truncateRat)
 }
 
 // funFloor implements (truncte X). It returns the greatest integer value less than or equal to x.
 func funFloor(ctx context.Context, w *World, arg Node) (Node, error) {
-	return floatToInteger(ctx, w, arg, math.Floor)
+	return floatToInteger(ctx, w, arg, math.Floor, floorRat)
 }
 
 // funCeiling implements (ceiling X). It returns the least integer value greater than or equal to x.
 func funCeiling(ctx context.Context, w *World, arg Node) (Node, error) {
-	return floatToInteger(ctx, w, arg, math.Ceil)
+	return floatToInteger(ctx, w, arg, math.Ceil, ceilingRat)
 }
 
 func funRound(ctx context.Context, w *World, arg Node) (Node, error) {
-	return floatToInteger(ctx, w, arg, math.Round)
+	return floatToInteger(ctx, w, arg, math.Round, roundRat)
 }
 
-func funMod(ctx context.Context, w *World, first, second Node) (Node, error) {
-	var left float64
-	var right float64
-	bits := 0
-
-	if _left, ok := first.(Integer); ok {
-		left = float64(int(_left))
-		bits = 1
-	} else if _left, err := ExpectClass[Float](ctx, w, first); err != nil {
+func isFloatOperand(first, second Node) bool {
+	if _, ok := first.(Float); ok {
+		return true
+	}
+	_, ok := second.(Float)
+	return ok
+}
+
+// floorDivMod returns the quotient of x/y rounded toward negative infinity
+// and the remainder which has the same sign as y.
+func floorDivMod(x, y *big.Int) (*big.Int, *big.Int) {
+	q, m := new(big.Int).QuoRem(x, y, new(big.Int))
+	if m.Sign() != 0 && m.Sign() != y.Sign() {
+		q.Sub(q, big.NewInt(1))
+		m.Add(m, y)
+	}
+	return q, m
+}
+
+// funDiv implements (div Z1 Z2). It returns the greatest integer less than or equal to Z1/Z2.
+func funDiv(ctx context.Context, w *World, first, second Node) (Node, error) {
+	if left, ok := first.(Integer); ok {
+		// math.MinInt64 / -1 overflows
+		if right, ok := second.(Integer); ok && right != 0 && right != -1 {
+			q := left / right
+			if left%right != 0 && (left < 0) != (right < 0) {
+				q--
+			}
+			return q, nil
+		}
+	}
+	left, err := expectBigInt(ctx, w, first)
+	if err != nil {
 		return nil, err
-	} else {
-		left = float64(_left)
 	}
+	right, err := expectBigInt(ctx, w, second)
+	if err != nil {
+		return nil, err
+	}
+	if right.Sign() == 0 {
+		return raiseDivisionByZero(ctx, w, first, second)
+	}
+	q, _ := floorDivMod(left, right)
+	return integerOf(q), nil
+}
 
-	if _right, ok := second.(Integer); ok {
-		right = float64(int(_right))
-		bits |= 2
-	} else if _right, err := ExpectClass[Float](ctx, w, second); err != nil {
+// funMod implements (mod Z1 Z2). It returns the remainder of (div Z1 Z2) which has the same sign as Z2.
+func funMod(ctx context.Context, w *World, first, second Node) (Node, error) {
+	if left, ok := first.(Integer); ok {
+		if right, ok := second.(Integer); ok && right != 0 {
+			m := left % right
+			if m != 0 && (m < 0) != (right < 0) {
+				m += right
+			}
+			return m, nil
+		}
+	}
+	if isFloatOperand(first, second) {
+		left, err := expectFloat64(ctx, w, first)
+		if err != nil {
+			return nil, err
+		}
+		right, err := expectFloat64(ctx, w, second)
+		if err != nil {
+			return nil, err
+		}
+		if right == 0 {
+			return raiseDivisionByZero(ctx, w, first, second)
+		}
+		m := math.Mod(left, right)
+		if m != 0 && (m < 0) != (right < 0) {
+			m += right
+		}
+		return Float(m), nil
+	}
+	left, err := expectBigInt(ctx, w, first)
+	if err != nil {
 		return nil, err
-	} else {
-		right = float64(_right)
 	}
-	value := math.Remainder(left, right)
-	if bits == 3 {
-		return Integer(int(value)), nil
+	right, err := expectBigInt(ctx, w, second)
+	if err != nil {
+		return nil, err
 	}
-	return Float(value), nil
+	if right.Sign() == 0 {
+		return raiseDivisionByZero(ctx, w, first, second)
+	}
+	_, m := floorDivMod(left, right)
+	return integerOf(m), nil
 }
 
+// funRem implements (rem Z1 Z2). It returns the remainder which has the same sign as Z1.
 func funRem(ctx context.Context, w *World, first, second Node) (Node, error) {
 	if left, ok := first.(Integer); ok {
-		if right, ok := second.(Integer); ok {
-			return Integer(left % right), nil
+		if right, ok := second.(Integer); ok && right != 0 {
+			return left % right, nil
+		}
+	}
+	if isFloatOperand(first, second) {
+		left, err := expectFloat64(ctx, w, first)
+		if err != nil {
+			return nil, err
 		}
+		right, err := expectFloat64(ctx, w, second)
+		if err != nil {
+			return nil, err
+		}
+		if right == 0 {
+			return raiseDivisionByZero(ctx, w, first, second)
+		}
+		return Float(math.Mod(left, right)), nil
+	}
+	left, err := expectBigInt(ctx, w, first)
+	if err != nil {
+		return nil, err
+	}
+	right, err := expectBigInt(ctx, w, second)
+	if err != nil {
+		return nil, err
+	}
+	if right.Sign() == 0 {
+		return raiseDivisionByZero(ctx, w, first, second)
 	}
-	left, err := ExpectClass[Float](ctx, w, first)
+	return integerOf(new(big.Int).Rem(left, right)), nil
+}
+
+// funGcd implements (gcd Z1 Z2). It returns the greatest common divisor of Z1 and Z2.
+func funGcd(ctx context.Context, w *World, first, second Node) (Node, error) {
+	left, err := expectBigInt(ctx, w, first)
 	if err != nil {
 		return nil, err
 	}
-	if right, ok := second.(Integer); ok {
-		return Float(math.Mod(float64(left), float64(int(right)))), nil
+	right, err := expectBigInt(ctx, w, second)
+	if err != nil {
+		return nil, err
 	}
-	right, err := ExpectClass[Float](ctx, w, second)
+	return integerOf(new(big.Int).GCD(nil, nil, left, right)), nil
+}
+
+// funLcm implements (lcm Z1 Z2). It returns the least common multiple of Z1 and Z2.
+func funLcm(ctx context.Context, w *World, first, second Node) (Node, error) {
+	left, err := expectBigInt(ctx, w, first)
 	if err != nil {
 		return nil, err
 	}
-	return Float(math.Mod(float64(left), float64(right))), nil
+	right, err := expectBigInt(ctx, w, second)
+	if err != nil {
+		return nil, err
+	}
+	if left.Sign() == 0 || right.Sign() == 0 {
+		return Integer(0), nil
+	}
+	gcd := new(big.Int).GCD(nil, nil, left, right)
+	lcm := new(big.Int).Mul(left, right)
+	lcm.Abs(lcm)
+	return integerOf(lcm.Quo(lcm, gcd)), nil
 }
@@ -23,6 +23,7 @@ type stdFactory struct{}
 func (stdFactory) Cons(car, cdr Node) Node           { return &Cons{Car: car, Cdr: cdr} }
 func (stdFactory) Int(n int64) Node                  { return Integer(n) }
 func (stdFactory) BigInt(n *big.Int) Node            { return BigInt{Int: n} }
+func (stdFactory) Ratio(r *big.Rat) Node             { return Ratio{Rat: r} }
 func (stdFactory) Float(f float64) Node              { return Float(f) }
 func (stdFactory) String(s string) Node              { return String(s) }
 func (stdFactory) Array(list []Node, dim []int) Node { return &Array{list: list, dim: dim} }
@@ -32,6 +33,13 @@ func (stdFactory) Symbol(s string) Node              { return NewSymbol(s) }
 func (stdFactory) Null() Node                        { return Null }
 func (stdFactory) True() Node                        { return True }
 
+func (stdFactory) ConsAt(car, cdr Node, pos Position) Node {
+	return &Cons{Car: car, Cdr: cdr, pos: &pos}
+}
+
+// Position is the place in the source where a form was read.
+type Position = parser.Position
+
 func ReadNode(rs io.RuneScanner) (Node, error) {
 	return parser.Read[Node](stdFactory{}, rs)
 }
@@ -45,14 +53,24 @@ func newQuote(value Node) Node {
 }
 
 func ReadAll(rs io.RuneScanner) ([]Node, error) {
+	return readAll(rs, "")
+}
+
+// readAll reads all forms from rs recording the positions in the file fname
+// on the conses.
+func readAll(rs io.RuneScanner, fname string) ([]Node, error) {
+	pr, ok := rs.(*parser.Reader)
+	if !ok {
+		pr = parser.NewReader(rs, fname)
+	}
 	result := []Node{}
 	for {
-		token, err := ReadNode(rs)
+		token, err := ReadNode(pr)
 		if err != nil {
 			if err == io.EOF {
 				return result, nil
 			}
-			return nil, err
+			return nil, &PositionError{Err: err, Pos: pr.Position()}
 		}
 		result = append(result, token)
 	}
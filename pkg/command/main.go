@@ -8,11 +8,19 @@ import (
 	"github.com/hymkor/gmnlisp"
 )
 
+// Extension is the functions of this package to give gmnlisp.NewWithExtensions.
+var Extension = gmnlisp.Functions{
+	gmnlisp.NewSymbol("command"): &gmnlisp.Function{F: funCommand},
+}
+
 func init() {
-	gmnlisp.Export(gmnlisp.NewSymbol("command"), &gmnlisp.Function{F: funCommand})
+	gmnlisp.ExportRange(Extension)
 }
 
 func funCommand(ctx context.Context, w *gmnlisp.World, list []gmnlisp.Node) (gmnlisp.Node, error) {
+	if err := w.RequireCapability(ctx, gmnlisp.CapProcess, "command"); err != nil {
+		return nil, err
+	}
 	argv := make([]string, len(list))
 	for i, value := range list {
 		argv[i] = value.String()
@@ -18,6 +18,7 @@ var (
 	rxHexInteger = regexp.MustCompile(`^\#[Xx][0-9A-Fa-f]+$`)
 	rxOctInteger = regexp.MustCompile(`^\#[Oo][0-7]+$`)
 	rxBinInteger = regexp.MustCompile(`^\#[Bb][01]+$`)
+	rxRatio      = regexp.MustCompile(`^-?[0-9]+/[0-9]+$`)
 	rxArray      = regexp.MustCompile(`^#(\d*)[aA]\(`)
 )
 
@@ -42,15 +43,33 @@ type Factory[N comparable] interface {
 	True() N
 }
 
+// RatioFactory is the Factory which can make the rational numbers like 1/3.
+// Without it, such tokens are read as symbols.
+type RatioFactory[N comparable] interface {
+	Factory[N]
+	Ratio(*big.Rat) N
+}
+
 type _Parser[N comparable] struct {
 	Factory[N]
+	posFactory PositionFactory[N]
+	ratFactory RatioFactory[N]
 
 	dotSymbol        N
 	functionSymbol   N
 	parenCloseSymbol N
 }
 
-func (p *_Parser[N]) nodes2cons(nodes []N) N {
+// consAt makes a cons and records pos on it when both of the reader and
+// the factory support positions.
+func (p *_Parser[N]) consAt(car, cdr N, pos Position) N {
+	if p.posFactory != nil && pos.Line > 0 {
+		return p.posFactory.ConsAt(car, cdr, pos)
+	}
+	return p.Cons(car, cdr)
+}
+
+func (p *_Parser[N]) nodes2cons(nodes []N, positions []Position, listPos Position) N {
 	if nodes == nil || len(nodes) <= 0 {
 		return p.Null()
 	}
@@ -61,7 +80,11 @@ func (p *_Parser[N]) nodes2cons(nodes []N) N {
 		nodes = nodes[:len(nodes)-2]
 	}
 	for i := len(nodes) - 1; i >= 0; i-- {
-		cons = p.Cons(nodes[i], cons)
+		pos := positions[i]
+		if i == 0 {
+			pos = listPos
+		}
+		cons = p.consAt(nodes[i], cons, pos)
 	}
 	return cons
 }
@@ -131,8 +154,9 @@ func (p *_Parser[N]) newQuote(value N) N {
 	return p.Cons(p.Symbol("quote"), p.Cons(value, p.Null()))
 }
 
-func (p *_Parser[N]) newBackQuote(value N) N {
-	return p.Cons(p.Symbol("quasiquote"), p.Cons(value, p.Null()))
+// wrap makes (name value) recording pos on the outer cons.
+func (p *_Parser[N]) wrap(name, value N, pos Position) N {
+	return p.consAt(name, p.Cons(value, p.Null()), pos)
 }
 
 func (p *_Parser[N]) tryParseAsFloat(token string) (N, bool, error) {
@@ -179,7 +203,27 @@ func (p *_Parser[N]) tryParseAsInt(token string) (N, bool, error) {
 	return p.Int(val), true, nil
 }
 
+func (p *_Parser[N]) tryParseAsRatio(token string) (N, bool, error) {
+	if p.ratFactory == nil || !rxRatio.MatchString(token) {
+		return p.Null(), false, nil
+	}
+	var val big.Rat
+	if _, ok := val.SetString(token); !ok {
+		return p.Null(), true, fmt.Errorf("%w: (%s)", ErrCanNotParseNumber, token)
+	}
+	if val.IsInt() {
+		if num := val.Num(); num.IsInt64() {
+			return p.Int(num.Int64()), true, nil
+		}
+		return p.BigInt(val.Num()), true, nil
+	}
+	return p.ratFactory.Ratio(&val), true, nil
+}
+
 func (p *_Parser[N]) tryParseAsNumber(token string) (N, bool, error) {
+	if val, ok, err := p.tryParseAsRatio(token); ok {
+		return val, true, err
+	}
 	if val, ok, err := p.tryParseAsFloat(token); ok {
 		if err != nil {
 			return p.Null(), true, fmt.Errorf("%w: (%s)", ErrCanNotParseNumber, err.Error())
@@ -195,28 +239,22 @@ func (p *_Parser[N]) tryParseAsNumber(token string) (N, bool, error) {
 	return p.Null(), false, nil
 }
 
-func (p *_Parser[N]) readUntilCloseParen(rs io.RuneScanner) ([]N, error) {
+func (p *_Parser[N]) readUntilCloseParen(rs io.RuneScanner) ([]N, []Position, error) {
 	nodes := []N{}
-	var err error
+	positions := []Position{}
 	for {
+		node1, pos, err := p.readNodeAt(rs)
 		if err != nil {
 			if err == io.EOF {
-				return nil, ErrTooShortTokens
-			}
-			return nil, err
-		}
-		var node1 N
-		node1, err = p.ReadNode(rs)
-		if err != nil {
-			if err == io.EOF {
-				return nil, ErrTooShortTokens
+				return nil, nil, ErrTooShortTokens
 			}
-			return nil, err
+			return nil, nil, err
 		}
 		if node1 == p.parenCloseSymbol {
-			return nodes, nil
+			return nodes, positions, nil
 		}
 		nodes = append(nodes, node1)
+		positions = append(positions, pos)
 	}
 }
 
@@ -225,10 +263,21 @@ var symbol4barReplacer = strings.NewReplacer(
 	`\|`, `|`)
 
 func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
-	token, err := readToken(rs)
+	node, _, err := p.readNodeAt(rs)
+	return node, err
+}
+
+// readNodeAt returns the next node and the position where it begins.
+func (p *_Parser[N]) readNodeAt(rs io.RuneScanner) (N, Position, error) {
+	token, pos, err := readTokenAt(rs)
 	if err != nil {
-		return p.Null(), err
+		return p.Null(), pos, err
 	}
+	node, err := p.readNodeFrom(token, pos, rs)
+	return node, pos, err
+}
+
+func (p *_Parser[N]) readNodeFrom(token string, pos Position, rs io.RuneScanner) (N, error) {
 	if token == "`" {
 		quoted, err := p.ReadNode(rs)
 		if err != nil {
@@ -237,7 +286,7 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 			}
 			return p.Null(), err
 		}
-		return p.newBackQuote(quoted), nil
+		return p.wrap(p.Symbol("quasiquote"), quoted, pos), nil
 	}
 	if token == "'" {
 		quoted, err := p.ReadNode(rs)
@@ -247,7 +296,7 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 			}
 			return p.Null(), err
 		}
-		return p.newQuote(quoted), nil
+		return p.wrap(p.Symbol("quote"), quoted, pos), nil
 	}
 	if token == "," {
 		quoted, err := p.ReadNode(rs)
@@ -257,7 +306,7 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 			}
 			return p.Null(), err
 		}
-		return p.Cons(p.Symbol("unquote"), p.Cons(quoted, p.Null())), nil
+		return p.wrap(p.Symbol("unquote"), quoted, pos), nil
 	}
 	if token == "#'" {
 		function, err := p.ReadNode(rs)
@@ -267,7 +316,7 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 			}
 			return p.Null(), err
 		}
-		return p.Cons(p.functionSymbol, p.Cons(function, p.Null())), nil
+		return p.wrap(p.functionSymbol, function, pos), nil
 	}
 	if token == "#(" {
 		return p.readArray(1, rs)
@@ -280,11 +329,11 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 		return p.readArray(dim, rs)
 	}
 	if token == "(" {
-		nodes, err := p.readUntilCloseParen(rs)
+		nodes, positions, err := p.readUntilCloseParen(rs)
 		if err != nil {
 			return p.Null(), err
 		}
-		return p.nodes2cons(nodes), nil
+		return p.nodes2cons(nodes, positions, pos), nil
 	}
 	if len(token) > 0 && (token[0] == ':' || token[0] == '&') {
 		return p.Keyword(token), nil
@@ -357,8 +406,12 @@ func (p *_Parser[N]) ReadNode(rs io.RuneScanner) (N, error) {
 }
 
 func newParser[N comparable](f Factory[N]) *_Parser[N] {
+	posFactory, _ := f.(PositionFactory[N])
+	ratFactory, _ := f.(RatioFactory[N])
 	return &_Parser[N]{
 		Factory:          f,
+		posFactory:       posFactory,
+		ratFactory:       ratFactory,
 		dotSymbol:        f.Symbol("."),
 		functionSymbol:   f.Symbol("function"),
 		parenCloseSymbol: f.Symbol(")"),
@@ -0,0 +1,76 @@
+package parser
+
+import (
+	"fmt"
+	"io"
+)
+
+// Position is the place in the source where a form begins.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+func (p Position) String() string {
+	if p.File == "" {
+		return fmt.Sprintf("%d:%d", p.Line, p.Column)
+	}
+	return fmt.Sprintf("%s:%d:%d", p.File, p.Line, p.Column)
+}
+
+// Reader is an io.RuneScanner which counts lines and columns of the runes read.
+// When ReadNode is given a *Reader, the positions of the forms are passed to
+// the Factory implementing PositionFactory.
+type Reader struct {
+	io.RuneScanner
+	File string
+
+	line, column         int
+	lastLine, lastColumn int
+}
+
+func NewReader(rs io.RuneScanner, file string) *Reader {
+	return &Reader{RuneScanner: rs, File: file}
+}
+
+func (r *Reader) ReadRune() (rune, int, error) {
+	c, size, err := r.RuneScanner.ReadRune()
+	if err != nil {
+		return c, size, err
+	}
+	r.lastLine, r.lastColumn = r.line, r.column
+	if c == '\n' {
+		r.line++
+		r.column = 0
+	} else {
+		r.column++
+	}
+	return c, size, nil
+}
+
+func (r *Reader) UnreadRune() error {
+	if err := r.RuneScanner.UnreadRune(); err != nil {
+		return err
+	}
+	r.line, r.column = r.lastLine, r.lastColumn
+	return nil
+}
+
+// Position returns the position of the rune which will be read next.
+func (r *Reader) Position() Position {
+	return Position{File: r.File, Line: r.line + 1, Column: r.column + 1}
+}
+
+func positionOf(rs io.RuneScanner) (Position, bool) {
+	if r, ok := rs.(*Reader); ok {
+		return r.Position(), true
+	}
+	return Position{}, false
+}
+
+// PositionFactory is the Factory which records where each cons was read.
+type PositionFactory[N comparable] interface {
+	Factory[N]
+	ConsAt(car, cdr N, pos Position) N
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+)
+
+type testNode struct {
+	car, cdr *testNode
+	atom     string
+	pos      Position
+}
+
+type testFactory struct{}
+
+func (testFactory) Cons(car, cdr *testNode) *testNode { return &testNode{car: car, cdr: cdr} }
+func (testFactory) ConsAt(car, cdr *testNode, pos Position) *testNode {
+	return &testNode{car: car, cdr: cdr, pos: pos}
+}
+func (testFactory) Int(n int64) *testNode              { return &testNode{atom: "int"} }
+func (testFactory) BigInt(*big.Int) *testNode          { return &testNode{atom: "bigint"} }
+func (testFactory) Float(float64) *testNode            { return &testNode{atom: "float"} }
+func (testFactory) String(s string) *testNode          { return &testNode{atom: s} }
+func (testFactory) Symbol(s string) *testNode          { return symbols(s) }
+func (testFactory) Array([]*testNode, []int) *testNode { return &testNode{atom: "array"} }
+func (testFactory) Keyword(s string) *testNode         { return &testNode{atom: s} }
+func (testFactory) Rune(rune) *testNode                { return &testNode{atom: "rune"} }
+func (testFactory) Null() *testNode                    { return nil }
+func (testFactory) True() *testNode                    { return &testNode{atom: "t"} }
+
+var symbolTable = map[string]*testNode{}
+
+func symbols(s string) *testNode {
+	if n, ok := symbolTable[s]; ok {
+		return n
+	}
+	n := &testNode{atom: s}
+	symbolTable[s] = n
+	return n
+}
+
+func TestPosition(t *testing.T) {
+	r := NewReader(strings.NewReader("; comment\n  (foo\n   (bar 1) 'baz)"), "test.lsp")
+	node, err := Read[*testNode](testFactory{}, r)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	expect := func(n *testNode, line, column int) {
+		t.Helper()
+		if n.pos.File != "test.lsp" || n.pos.Line != line || n.pos.Column != column {
+			t.Fatalf("expected test.lsp:%d:%d, but %s", line, column, n.pos.String())
+		}
+	}
+	expect(node, 2, 3)         // (foo ...
+	expect(node.cdr, 3, 4)     // (bar 1) as an element
+	expect(node.cdr.car, 3, 4) // (bar 1) as a list
+	expect(node.cdr.cdr, 3, 12)
+	expect(node.cdr.cdr.car, 3, 12) // 'baz
+}
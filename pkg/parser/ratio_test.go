@@ -0,0 +1,32 @@
+package parser
+
+import (
+	"math/big"
+	"testing"
+)
+
+type ratioFactory struct {
+	testFactory
+}
+
+func (ratioFactory) Ratio(r *big.Rat) *testNode { return &testNode{atom: r.String()} }
+
+func TestRatio(t *testing.T) {
+	for token, expect := range map[string]string{
+		"1/3":  "1/3",
+		"-2/4": "-1/2",
+		"4/2":  "int",
+		"1.5":  "float",
+	} {
+		node, ok, err := TryParseAsNumber[*testNode](ratioFactory{}, token)
+		if err != nil || !ok {
+			t.Fatalf("%s: not parsed as number: %v", token, err)
+		}
+		if node.atom != expect {
+			t.Fatalf("%s: expected %s, but %s", token, expect, node.atom)
+		}
+	}
+	if _, ok, _ := TryParseAsNumber[*testNode](testFactory{}, "1/3"); ok {
+		t.Fatal("1/3 must not be a number without RatioFactory")
+	}
+}
@@ -121,10 +121,17 @@ func readtokenWord(r io.RuneScanner) (string, error) {
 }
 
 func readToken(r io.RuneScanner) (string, error) {
+	token, _, err := readTokenAt(r)
+	return token, err
+}
+
+// readTokenAt returns the next token and the position where it begins.
+func readTokenAt(r io.RuneScanner) (string, Position, error) {
 	for {
+		pos, _ := positionOf(r)
 		lastRune, _, err := r.ReadRune()
 		if err != nil {
-			return "", err
+			return "", pos, err
 		}
 		if unicode.IsSpace(lastRune) {
 			continue
@@ -137,7 +144,7 @@ func readToken(r io.RuneScanner) (string, error) {
 		}
 		if strings.ContainsRune("',`()", lastRune) {
 			token := string(lastRune)
-			return token, nil
+			return token, pos, nil
 		}
 		if e := r.UnreadRune(); e != nil {
 			panic(e.Error())
@@ -145,7 +152,7 @@ func readToken(r io.RuneScanner) (string, error) {
 		var token string
 		token, err = readtokenWord(r)
 		if token != "" {
-			return token, nil
+			return token, pos, nil
 		}
 	}
 }
@@ -3,16 +3,23 @@ package regexp
 import (
 	"context"
 	"regexp"
+	"sync"
 
 	. "github.com/hymkor/gmnlisp"
 )
 
+// Extension is the functions of this package to give NewWithExtensions.
+var Extension = Functions{
+	NewSymbol("=~"):  &Function{C: 2, F: funFindAllStringSubmatch},
+	NewSymbol("=~i"): &Function{C: 2, F: funFindAllStringSubmatchIndex},
+}
+
 func init() {
-	Export(NewSymbol("=~"), &Function{C: 2, F: funFindAllStringSubmatch})
-	Export(NewSymbol("=~i"), &Function{C: 2, F: funFindAllStringSubmatchIndex})
+	ExportRange(Extension)
 }
 
-var regexpCache = map[string]*regexp.Regexp{}
+// regexpCache is shared by all the Worlds.
+var regexpCache sync.Map
 
 func getRegexpParam(ctx context.Context, w *World, list []Node) (*regexp.Regexp, string, error) {
 	_pattern, err := ExpectClass[String](ctx, w, list[0])
@@ -20,13 +27,16 @@ func getRegexpParam(ctx context.Context, w *World, list []Node) (*regexp.Regexp,
 		return nil, "", err
 	}
 	pattern := _pattern.String()
-	reg, ok := regexpCache[pattern]
-	if !ok {
+	var reg *regexp.Regexp
+	if value, ok := regexpCache.Load(pattern); ok {
+		reg = value.(*regexp.Regexp)
+	} else {
 		var err error
 		reg, err = regexp.Compile(pattern)
 		if err != nil {
 			return nil, "", MakeError(err, pattern)
 		}
+		regexpCache.Store(pattern, reg)
 	}
 	str, err := ExpectClass[String](ctx, w, list[1])
 	if err != nil {
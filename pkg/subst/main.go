@@ -6,8 +6,13 @@ import (
 	. "github.com/hymkor/gmnlisp"
 )
 
+// Extension is the functions of this package to give NewWithExtensions.
+var Extension = Functions{
+	NewSymbol("subst"): &Function{C: 3, F: funSubst},
+}
+
 func init() {
-	Export(NewSymbol("subst"), &Function{C: 3, F: funSubst})
+	ExportRange(Extension)
 }
 
 func subst(newItem, oldItem, list Node) Node {
@@ -8,8 +8,13 @@ import (
 	"github.com/hymkor/gmnlisp"
 )
 
+// Extension is the functions of this package to give gmnlisp.NewWithExtensions.
+var Extension = gmnlisp.Functions{
+	gmnlisp.NewSymbol("wildcard"): &gmnlisp.Function{F: funWildcard},
+}
+
 func init() {
-	gmnlisp.Export(gmnlisp.NewSymbol("wildcard"), &gmnlisp.Function{F: funWildcard})
+	gmnlisp.ExportRange(Extension)
 }
 
 func funWildcard(ctx context.Context, w *gmnlisp.World, list []gmnlisp.Node) (gmnlisp.Node, error) {
@@ -17,6 +22,9 @@ func funWildcard(ctx context.Context, w *gmnlisp.World, list []gmnlisp.Node) (gm
 	if err != nil {
 		return nil, err
 	}
+	if err := w.RequireCapability(ctx, gmnlisp.CapFileRead, "wildcard"); err != nil {
+		return nil, err
+	}
 	files, err := filepath.Glob(pattern.String())
 	if err != nil {
 		return nil, fmt.Errorf("%w: %#v", err, pattern)
@@ -87,16 +87,24 @@ func cmdBlock(ctx context.Context, w *World, node Node) (Node, error) {
 	} else {
 		nameSymbol = nulSymbol
 	}
+	return inBlock(ctx, w, nameSymbol, func(ctx context.Context, w *World) (Node, error) {
+		return Progn(ctx, w, statements)
+	})
+}
+
+// inBlock calls body in the block named name, where return-from name
+// can be used.
+func inBlock(ctx context.Context, w *World, name Symbol, body _Compiled) (Node, error) {
 	if w.blockName == nil {
 		w.blockName = make(map[Symbol]struct{})
 	}
-	if _, ok := w.blockName[nameSymbol]; !ok {
-		defer delete(w.blockName, nameSymbol)
-		w.blockName[nameSymbol] = struct{}{}
+	if _, ok := w.blockName[name]; !ok {
+		defer delete(w.blockName, name)
+		w.blockName[name] = struct{}{}
 	}
 	var errEarlyReturns *_ErrEarlyReturns
-	rv, err := Progn(ctx, w, statements)
-	if errors.As(err, &errEarlyReturns) && errEarlyReturns.Name == nameSymbol {
+	rv, err := body(ctx, w)
+	if errors.As(err, &errEarlyReturns) && errEarlyReturns.Name == name {
 		return errEarlyReturns.Value, nil
 	}
 	return rv, err
@@ -144,10 +152,6 @@ func funThrow(ctx context.Context, w *World, tagForm, value Node) (Node, error)
 }
 
 func cmdCond(ctx context.Context, w *World, list Node) (Node, error) {
-	return cmdCondWithTailRecOpt(ctx, w, list, _Symbol(-1))
-}
-
-func cmdCondWithTailRecOpt(ctx context.Context, w *World, list Node, currFunc Symbol) (Node, error) {
 	for IsSome(list) {
 		var condAndAct Node
 		var err error
@@ -161,7 +165,7 @@ func cmdCondWithTailRecOpt(ctx context.Context, w *World, list Node, currFunc Sy
 			return nil, err
 		}
 		if IsSome(cond) {
-			return prognWithTailRecOpt(ctx, w, act, currFunc)
+			return Progn(ctx, w, act)
 		}
 	}
 	return Null, nil
@@ -207,10 +211,6 @@ func cmdCase(ctx context.Context, w *World, list Node) (Node, error) {
 }
 
 func cmdIf(ctx context.Context, w *World, params Node) (Node, error) {
-	return cmdIfWithTailRecOpt(ctx, w, params, _Symbol(-1))
-}
-
-func cmdIfWithTailRecOpt(ctx context.Context, w *World, params Node, tailOptSym Symbol) (Node, error) {
 	cond, params, err := w.ShiftAndEvalCar(ctx, params)
 	if err != nil {
 		return nil, err
@@ -230,9 +230,9 @@ func cmdIfWithTailRecOpt(ctx context.Context, w *World, params Node, tailOptSym
 		}
 	}
 	if IsSome(cond) {
-		return evalWithTailRecOpt(ctx, w, thenClause, tailOptSym)
+		return w.Eval(ctx, thenClause)
 	} else if IsSome(elseClause) {
-		return evalWithTailRecOpt(ctx, w, elseClause, tailOptSym)
+		return w.Eval(ctx, elseClause)
 	} else {
 		return Null, nil
 	}
@@ -278,10 +278,7 @@ func cmdUnwindProtect(ctx context.Context, w *World, list Node) (Node, error) {
 
 	value, err := Progn(ctx, w, list)
 	if err != nil {
-		var e1 *_ErrEarlyReturns // block & return-from
-		var e2 *_ErrThrown       // catch & throw
-		var e3 *_ErrTagBody      // tagbody & go
-		if errors.As(err, &e1) || errors.As(err, &e2) || errors.As(err, &e3) {
+		if IsNonLocalExists(err) {
 			return raiseControlError(ctx, w, errors.New("can not escape from cleanup-form"))
 		}
 		return nil, err
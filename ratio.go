@@ -0,0 +1,164 @@
+package gmnlisp
+
+import (
+	"context"
+	"math/big"
+)
+
+// Ratio is the exact rational number which is not an integer such as 1/3.
+// The results of the arithmetic are demoted to Integer or BigInt when the
+// denominators become 1.
+type Ratio struct {
+	*big.Rat
+}
+
+var ratioClass = registerClass(&_BuiltInClass{
+	name: NewSymbol("<ratio>"),
+	instanceP: func(n Node) bool {
+		_, ok := n.(Ratio)
+		return ok
+	},
+	create: func() Node {
+		return Integer(0)
+	},
+}, numberClass)
+
+func (r Ratio) ClassOf() Class {
+	return ratioClass
+}
+
+// ratioOf returns v as Ratio, or as Integer or BigInt when v is an integer.
+func ratioOf(v *big.Rat) Node {
+	if v.IsInt() {
+		return integerOf(new(big.Int).Set(v.Num()))
+	}
+	return Ratio{Rat: v}
+}
+
+// expectRat returns the value of the Integer, the BigInt or the Ratio n.
+func expectRat(ctx context.Context, w *World, n Node) (*big.Rat, error) {
+	switch v := n.(type) {
+	case Ratio:
+		return v.Rat, nil
+	case BigInt:
+		return new(big.Rat).SetInt(v.Int), nil
+	case Integer:
+		return new(big.Rat).SetInt64(int64(v)), nil
+	}
+	v, err := ExpectClass[Ratio](ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return v.Rat, nil
+}
+
+func (r Ratio) Equals(n Node, m EqlMode) bool {
+	if _n, ok := n.(Ratio); ok {
+		return r.Rat.Cmp(_n.Rat) == 0
+	}
+	if m == EQUALP {
+		if _n, ok := n.(Float); ok {
+			return r.Float() == _n
+		}
+	}
+	return false
+}
+
+// Float returns the nearest Float value of r.
+func (r Ratio) Float() Float {
+	f, _ := r.Rat.Float64()
+	return Float(f)
+}
+
+func (r Ratio) Add(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Float); ok {
+		return r.Float().Add(ctx, w, _n)
+	}
+	_n, err := expectRat(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return ratioOf(new(big.Rat).Add(r.Rat, _n)), nil
+}
+
+func (r Ratio) Sub(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Float); ok {
+		return r.Float().Sub(ctx, w, _n)
+	}
+	_n, err := expectRat(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return ratioOf(new(big.Rat).Sub(r.Rat, _n)), nil
+}
+
+func (r Ratio) Multi(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Float); ok {
+		return r.Float().Multi(ctx, w, _n)
+	}
+	_n, err := expectRat(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	return ratioOf(new(big.Rat).Mul(r.Rat, _n)), nil
+}
+
+func (r Ratio) Divide(ctx context.Context, w *World, n Node) (Node, error) {
+	if _n, ok := n.(Float); ok {
+		if _n == 0 {
+			return raiseDivisionByZero(ctx, w, r, n)
+		}
+		return r.Float().Divide(ctx, w, _n)
+	}
+	_n, err := expectRat(ctx, w, n)
+	if err != nil {
+		return nil, err
+	}
+	if _n.Sign() == 0 {
+		return raiseDivisionByZero(ctx, w, r, n)
+	}
+	return ratioOf(new(big.Rat).Quo(r.Rat, _n)), nil
+}
+
+func (r Ratio) LessThan(ctx context.Context, w *World, n Node) (bool, error) {
+	if _n, ok := n.(Float); ok {
+		return r.Float() < _n, nil
+	}
+	_n, err := expectRat(ctx, w, n)
+	if err != nil {
+		return false, err
+	}
+	return r.Rat.Cmp(_n) < 0, nil
+}
+
+// floorRat returns the greatest integer less than or equal to num/den.
+func floorRat(num, den *big.Int) *big.Int {
+	q, _ := floorDivMod(num, den)
+	return q
+}
+
+// ceilingRat returns the least integer greater than or equal to num/den.
+func ceilingRat(num, den *big.Int) *big.Int {
+	q, m := new(big.Int).QuoRem(num, den, new(big.Int))
+	if m.Sign() > 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	return q
+}
+
+// truncateRat returns the integer part of num/den.
+func truncateRat(num, den *big.Int) *big.Int {
+	return new(big.Int).Quo(num, den)
+}
+
+// roundRat returns the nearest integer of num/den rounding half away from
+// zero as math.Round does.
+func roundRat(num, den *big.Int) *big.Int {
+	twice := new(big.Int).Lsh(num, 1)
+	if num.Sign() < 0 {
+		twice.Sub(twice, den)
+	} else {
+		twice.Add(twice, den)
+	}
+	return twice.Quo(twice, new(big.Int).Lsh(den, 1))
+}
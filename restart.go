@@ -0,0 +1,132 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// _Restart is the restart established by restart-case.
+type _Restart struct {
+	name Symbol
+	f    Callable
+}
+
+var restartClass = registerNewAbstractClass[*_Restart]("<restart>")
+
+func (r *_Restart) ClassOf() Class {
+	return restartClass
+}
+
+func (r *_Restart) Equals(other Node, _ EqlMode) bool {
+	o, ok := other.(*_Restart)
+	return ok && o == r
+}
+
+func (r *_Restart) String() string {
+	return "{*_Restart}" + r.name.String()
+}
+
+// _ErrInvokeRestart is the non-local exit from invoke-restart to
+// the restart-case which established the restart.
+type _ErrInvokeRestart struct {
+	restart *_Restart
+	args    []Node
+}
+
+func (e *_ErrInvokeRestart) Error() string {
+	return fmt.Sprintf("restart %#v was not established", e.restart.name.String())
+}
+
+var errRestartNotFound = errors.New("restart not found")
+
+func cmdRestartCase(ctx context.Context, w *World, node Node) (Node, error) {
+	// (restart-case form (name lambda-list form*)*)
+	form, clauses, err := Shift(node)
+	if err != nil {
+		return raiseProgramError(ctx, w, err)
+	}
+	var established []*_Restart
+	for IsSome(clauses) {
+		var clause Node
+		clause, clauses, err = Shift(clauses)
+		if err != nil {
+			return raiseProgramError(ctx, w, err)
+		}
+		_name, rest, err := Shift(clause)
+		if err != nil {
+			return raiseProgramError(ctx, w, err)
+		}
+		name, err := ExpectSymbol(ctx, w, _name)
+		if err != nil {
+			return nil, err
+		}
+		f, err := newLambda(ctx, w, rest, nulSymbol)
+		if err != nil {
+			return nil, err
+		}
+		established = append(established, &_Restart{name: name, f: f})
+	}
+	save := w.restarts
+	w.restarts = save[:len(save):len(save)]
+	for i := len(established) - 1; i >= 0; i-- {
+		w.restarts = append(w.restarts, established[i])
+	}
+	value, err := w.Eval(ctx, form)
+	w.restarts = save
+
+	var invoked *_ErrInvokeRestart
+	if !errors.As(err, &invoked) {
+		return value, err
+	}
+	for _, r := range established {
+		if r == invoked.restart {
+			return r.f.Call(ctx, w, UnevalList(invoked.args...))
+		}
+	}
+	return value, err
+}
+
+// findRestart returns the innermost active restart which is r itself or
+// is named r.
+func findRestart(w *World, r Node) *_Restart {
+	for i := len(w.restarts) - 1; i >= 0; i-- {
+		if restart := w.restarts[i]; restart == r || restart.name == r {
+			return restart
+		}
+	}
+	return nil
+}
+
+func funInvokeRestart(ctx context.Context, w *World, args []Node) (Node, error) {
+	restart := findRestart(w, args[0])
+	if restart == nil {
+		return raiseControlError(ctx, w, fmt.Errorf("%s: %w", args[0].String(), errRestartNotFound))
+	}
+	return nil, &_ErrInvokeRestart{restart: restart, args: args[1:]}
+}
+
+func funFindRestart(ctx context.Context, w *World, arg Node) (Node, error) {
+	if restart := findRestart(w, arg); restart != nil {
+		return restart, nil
+	}
+	return Null, nil
+}
+
+// funComputeRestarts implements (compute-restarts [CONDITION]).
+// It returns the active restarts from the innermost one.
+func funComputeRestarts(ctx context.Context, w *World, _ []Node) (Node, error) {
+	var result ListBuilder
+	for i := len(w.restarts) - 1; i >= 0; i-- {
+		result.Add(ctx, w, w.restarts[i])
+	}
+	return result.Sequence(), nil
+}
+
+func funRestartName(ctx context.Context, w *World, arg Node) (Node, error) {
+	restart, err := ExpectClass[*_Restart](ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	return restart.name, nil
+}
@@ -0,0 +1,106 @@
+package gmnlisp
+
+import (
+	"context"
+	"strings"
+)
+
+// Capability is the set of the accesses to the host which the functions
+// of a World need. All of them are permitted by default.
+type Capability uint
+
+const (
+	// CapFileRead permits open-input-file, probe-file, file-length and so on.
+	CapFileRead Capability = 1 << iota
+	// CapFileWrite permits open-output-file and so on.
+	CapFileWrite
+	// CapLoad permits load.
+	CapLoad
+	// CapProcess permits the extensions which run other processes
+	// such as pkg/command.
+	CapProcess
+
+	CapAll = CapFileRead | CapFileWrite | CapLoad | CapProcess
+)
+
+var capabilityNames = []struct {
+	cap  Capability
+	name string
+}{
+	{CapFileRead, "file-read"},
+	{CapFileWrite, "file-write"},
+	{CapLoad, "load"},
+	{CapProcess, "process"},
+}
+
+func (c Capability) String() string {
+	var names []string
+	for _, p := range capabilityNames {
+		if c&p.cap != 0 {
+			names = append(names, p.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// SecurityError is raised when a function requires the Capability
+// which is not permitted.
+type SecurityError struct {
+	Capability Capability
+	Operation  string
+}
+
+var securityErrorClass = registerNewAbstractClass[SecurityError]("<security-error>", errorClass)
+
+func (e SecurityError) ClassOf() Class {
+	return securityErrorClass
+}
+
+func (e SecurityError) Equals(n Node, _ EqlMode) bool {
+	o, ok := n.(SecurityError)
+	return ok && o == e
+}
+
+func (e SecurityError) String() string {
+	return "security error: " + e.Operation + " requires " + e.Capability.String()
+}
+
+func (e SecurityError) Error() string {
+	return e.String()
+}
+
+// NewSandbox returns the instance which has only the given extensions
+// and permits only the given capabilities.
+func NewSandbox(caps Capability, ext ...Functions) *World {
+	w := NewWithExtensions(ext...)
+	w.Deny(CapAll &^ caps)
+	return w
+}
+
+// Deny forbids the functions requiring caps.
+func (w *World) Deny(caps Capability) {
+	w.denied |= caps
+}
+
+// Allow permits the functions requiring caps again.
+func (w *World) Allow(caps Capability) {
+	w.denied &^= caps
+}
+
+// Permitted returns true when all of caps are permitted.
+func (w *World) Permitted(caps Capability) bool {
+	return w.denied&caps == 0
+}
+
+// RequireCapability raises SecurityError unless all of caps are permitted.
+// The extensions accessing the host have to call it before doing so.
+func (w *World) RequireCapability(ctx context.Context, caps Capability, operation string) error {
+	if w.Permitted(caps) {
+		return nil
+	}
+	_, err := callHandler[Node](ctx, w, false, SecurityError{
+		Capability: caps & w.denied,
+		Operation:  operation,
+	})
+	return err
+}
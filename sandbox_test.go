@@ -0,0 +1,86 @@
+package gmnlisp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSandbox(t *testing.T) {
+	w := NewSandbox(CapFileRead)
+	for _, code := range []string{
+		`(open-output-file "sandbox.txt")`,
+		`(with-open-output-file (s "sandbox.txt") (format s "x"))`,
+		`(open-io-file "sandbox.txt")`,
+		`(load "sandbox.lsp")`,
+	} {
+		_, err := w.Interpret(context.TODO(), code)
+		var e SecurityError
+		if !errors.As(err, &e) {
+			t.Fatalf("%s: SecurityError was not raised: %v", code, err)
+		}
+	}
+	if e := w.Assert(`(probe-file "world_test.go")`, True); e != "" {
+		t.Fatal(e)
+	}
+	w.Deny(CapFileRead)
+	if e := w.Assert(`(catch 'c
+			(with-handler
+				(lambda (c) (throw 'c (class-of c)))
+				(probe-file "world_test.go")))`, securityErrorClass); e != "" {
+		t.Fatal(e)
+	}
+	w.Allow(CapFileRead)
+	if _, err := w.Interpret(context.TODO(), `(file-length "world_test.go" 8)`); err != nil {
+		t.Fatal(err.Error())
+	}
+}
+
+func TestSetFS(t *testing.T) {
+	w := New()
+	w.SetFS(fstest.MapFS{
+		"lib/util.lsp": &fstest.MapFile{Data: []byte(`(defun twice (x) (* x 2))`)},
+		"data.txt":     &fstest.MapFile{Data: []byte("hello\nworld\n")},
+	})
+	if e := w.Assert(`(load "lib/util.lsp") (twice 21)`, Integer(42)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`(load "/lib/../lib/util.lsp") (twice 1)`, Integer(2)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`(with-open-input-file (s "data.txt") (read-line s) (read-line s))`, String("world")); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`(let ((s (open-input-file "data.txt")))
+			(read-line s)
+			(prog1 (file-position s) (close s)))`, Integer(6)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`(list (probe-file "data.txt") (probe-file "world_test.go"))`,
+		List(True, Null)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w.Assert(`(file-length "data.txt" 8)`, Integer(12)); e != "" {
+		t.Fatal(e)
+	}
+	for _, code := range []string{
+		`(open-output-file "setfs.txt")`,
+		`(with-open-output-file (s "setfs.txt") (format s "x"))`,
+		`(open-io-file "setfs.txt")`,
+	} {
+		_, err := w.Interpret(context.TODO(), code)
+		var e SecurityError
+		if !errors.As(err, &e) {
+			t.Fatalf("%s: SecurityError was not raised: %v", code, err)
+		}
+	}
+	if _, err := os.Stat("setfs.txt"); err == nil {
+		t.Fatal("setfs.txt was written to the host")
+	}
+	if e := w.Assert(`(let ((s (open-input-file "data.txt"))) (prog1 (list (eq s s) (equal s (standard-input))) (close s)))`,
+		List(True, Null)); e != "" {
+		t.Fatal(e)
+	}
+}
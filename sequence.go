@@ -5,6 +5,7 @@ import (
 	"io"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 type Sequence interface {
@@ -40,6 +41,9 @@ type ListBuilder struct {
 }
 
 func (L *ListBuilder) Add(ctx context.Context, w *World, n Node) error {
+	if err := w.useConses(ctx, 1); err != nil {
+		return err
+	}
 	tmp := &Cons{
 		Car: n,
 		Cdr: Null,
@@ -85,6 +89,9 @@ func (S *StringBuilder) Add(ctx context.Context, w *World, n Node) error {
 	if err != nil {
 		return err
 	}
+	if err := w.useStrings(ctx, int64(utf8.RuneLen(rune(r)))); err != nil {
+		return err
+	}
 	S.WriteRune(rune(r))
 	return nil
 }
@@ -311,7 +318,14 @@ func NReverse(ctx context.Context, w *World, list Node) (Node, error) {
 	return result, nil
 }
 
-func funReverse(_ context.Context, _ *World, arg Node) (Node, error) {
+func funReverse(ctx context.Context, w *World, arg Node) (Node, error) {
+	var length int64
+	for p, ok := arg.(*Cons); ok && IsSome(p); p, ok = p.Cdr.(*Cons) {
+		length++
+	}
+	if err := w.useConses(ctx, length); err != nil {
+		return nil, err
+	}
 	return Reverse(arg)
 }
 
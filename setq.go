@@ -64,7 +64,7 @@ func cmdPSetq(ctx context.Context, w *World, params Node) (Node, error) {
 	return Null, nil
 }
 
-func letValuesToVars(ctx context.Context, w *World, list Node, lexical map[Symbol]Node) error {
+func letValuesToVars(ctx context.Context, w *World, list Node, lexical *_Frame) error {
 	for IsSome(list) {
 		var item Node
 		var err error
@@ -74,7 +74,7 @@ func letValuesToVars(ctx context.Context, w *World, list Node, lexical map[Symbo
 			return err
 		}
 		if symbol, ok := item.(Symbol); ok {
-			lexical[symbol] = Null
+			lexical.bind(symbol, Null)
 			continue
 		}
 		var argv [2]Node
@@ -90,49 +90,41 @@ func letValuesToVars(ctx context.Context, w *World, list Node, lexical map[Symbo
 		if err != nil {
 			return err
 		}
-		lexical[symbol] = value
+		lexical.bind(symbol, value)
 	}
 	return nil
 }
 
 func cmdLet(ctx context.Context, w *World, params Node) (Node, error) {
-	return cmdLetWithTailRecOpt(ctx, w, params, _Symbol(-1))
-}
-
-func cmdLetWithTailRecOpt(ctx context.Context, w *World, params Node, tailOptSym Symbol) (Node, error) {
 	// from CommonLisp
 	list, params, err := Shift(params)
 	if err != nil {
 		return nil, err
 	}
-	lexical := Variables{}
+	lexical := newFrame(nil)
 
 	if err := letValuesToVars(ctx, w, list, lexical); err != nil {
 		return nil, err
 	}
 
 	newWorld := w.Let(lexical)
-	return prognWithTailRecOpt(ctx, newWorld, params, tailOptSym)
+	return Progn(ctx, newWorld, params)
 }
 
 func cmdLetX(ctx context.Context, w *World, params Node) (Node, error) {
-	return cmdLetXWithTailRecOpt(ctx, w, params, _Symbol(-1))
-}
-
-func cmdLetXWithTailRecOpt(ctx context.Context, w *World, params Node, tailOptSym Symbol) (Node, error) {
 	// from CommonLisp
 	list, params, err := Shift(params)
 	if err != nil {
 		return nil, err
 	}
-	lexical := Variables{}
+	lexical := newFrame(nil)
 
 	newWorld := w.Let(lexical)
 
 	if err := letValuesToVars(ctx, newWorld, list, lexical); err != nil {
 		return nil, err
 	}
-	return prognWithTailRecOpt(ctx, newWorld, params, tailOptSym)
+	return Progn(ctx, newWorld, params)
 }
 
 func cmdDefConstant(ctx context.Context, w *World, list Node) (Node, error) {
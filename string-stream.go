@@ -63,6 +63,9 @@ func funGetOutputStreamString(ctx context.Context, w *World, arg Node) (Node, er
 	if err != nil {
 		return nil, err
 	}
+	if err := w.useStrings(ctx, int64(stringer.Len())); err != nil {
+		return nil, err
+	}
 	result := String(stringer.String())
 	stringer.Reset()
 	return result, nil
@@ -92,6 +92,9 @@ func funStringAppend(ctx context.Context, w *World, list []Node) (Node, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err := w.useStrings(ctx, int64(len(str))); err != nil {
+			return nil, err
+		}
 		buffer.WriteString(str.String())
 	}
 	return String(buffer.String()), nil
@@ -189,5 +192,8 @@ func funCreateString(ctx context.Context, w *World, list []Node) (Node, error) {
 		}
 		return callHandler[Node](ctx, w, false, condition)
 	}
+	if err := w.useStrings(ctx, int64(length)*int64(utf8.RuneLen(rune(ch)))); err != nil {
+		return nil, err
+	}
 	return String(strings.Repeat(string(ch), int(length))), nil
 }
@@ -0,0 +1,9 @@
+(defun bt-f (x) (car x))
+(defun bt-g (y) (list (bt-f y)))
+(assert-eq
+  (block b
+    (with-handler
+      (lambda (c) (return-from b (cdr (backtrace))))
+      (bt-g 1)))
+  '((bt-f 1) (bt-g 1)))
+(assert-eq (backtrace) nil)
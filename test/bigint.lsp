@@ -0,0 +1,29 @@
+(assert-eq (+ 9223372036854775807 1) 9223372036854775808)
+(assert-eq (- -9223372036854775808 1) -9223372036854775809)
+(assert-eq (* 9223372036854775807 9223372036854775807)
+           85070591730234615847396907784232501249)
+(assert-eq (- (+ 9223372036854775807 1) 1) 9223372036854775807)
+(assert-eq (- -9223372036854775808) 9223372036854775808)
+(assert-eq (div 100000000000000000000 10000000000) 10000000000)
+(assert-eq (div -7 2) -4)
+(assert-eq (mod 5 3) 2)
+(assert-eq (mod 100000000000000000000 7) 2)
+(assert-eq (mod -100000000000000000000 7) 5)
+(assert-eq (rem -100000000000000000000 7) -2)
+(assert-eq (gcd 100000000000000000000 250) 250)
+(assert-eq (gcd -4 6) 2)
+(assert-eq (lcm 100000000000000000000 3) 300000000000000000000)
+(assert-eq (< 9223372036854775807 9223372036854775808) t)
+(assert-eq (> -9223372036854775808 -9223372036854775809) t)
+(assert-eq (= 100000000000000000000 (* 10000000000 10000000000)) t)
+(assert-eq (integerp 100000000000000000000) t)
+(assert-eq (evenp 100000000000000000000) t)
+(assert-eq (minusp -100000000000000000000) t)
+(assert-eq (format nil "~D" 100000000000000000000) "100000000000000000000")
+(assert-eq (format nil "~X" 100000000000000000000) "56BC75E2D63100000")
+(assert-eq (format nil "~B" 18446744073709551616)
+           "10000000000000000000000000000000000000000000000000000000000000000")
+(assert-eq (convert "100000000000000000000" <integer>) 100000000000000000000)
+(assert-eq (convert 100000000000000000000 <string>) "100000000000000000000")
+(assert-eq (parse-number "-100000000000000000000") -100000000000000000000)
+(assert-eq (truncate 1e20) 100000000000000000000)
@@ -0,0 +1,95 @@
+; the functions compiled into the bytecode by (compile)
+
+(defun bytecode-fib (n)
+  (if (< n 2) n (+ (bytecode-fib (- n 1)) (bytecode-fib (- n 2)))))
+(assert-eq (compile 'bytecode-fib) 'bytecode-fib)
+(assert-eq (bytecode-fib 15) 610)
+
+; let, let*, setq and while
+(defun bytecode-sum (n)
+  (let ((i 0) (s 0))
+    (while (< i n)
+      (let* ((j (+ i 1)) (k j))
+        (setq i k)
+        (setq s (+ s k))))
+    s))
+(compile 'bytecode-sum)
+(assert-eq (bytecode-sum 10) 55)
+
+; cond, case, and, or
+(defun bytecode-kind (x)
+  (cond
+    ((and (integerp x) (< x 0)) 'negative)
+    ((integerp x)
+     (case x
+       ((0) 'zero)
+       ((1 2) 'small)
+       (t 'large)))
+    ((or (stringp x) (characterp x)) 'text)
+    (t 'other)))
+(compile 'bytecode-kind)
+(assert-eq (mapcar #'bytecode-kind '(-1 0 2 9 "a" #\a nil))
+           '(negative zero small large text text other))
+
+; the tail calls do not grow the stack
+(defun bytecode-even (n) (if (= n 0) t (bytecode-odd (- n 1))))
+(defun bytecode-odd (n) (if (= n 0) nil (funcall #'bytecode-even (- n 1))))
+(compile 'bytecode-even)
+(compile 'bytecode-odd)
+(assert-eq (bytecode-even 100001) nil)
+
+; block, return-from and dolist
+(defun bytecode-find (x xs)
+  (block found
+    (dolist (y xs)
+      (if (equal x y) (return-from found y)))
+    nil))
+(compile 'bytecode-find)
+(assert-eq (bytecode-find 2 '(1 2 3)) 2)
+(assert-eq (bytecode-find 4 '(1 2 3)) nil)
+
+; the generic functions, the closures and the macros
+(defgeneric bytecode-twice (x))
+(defmethod bytecode-twice ((x <integer>)) (* x 2))
+(defmethod bytecode-twice ((x <string>)) (string-append x x))
+(defmacro bytecode-incf (v) (list 'setq v (list '+ v 1)))
+(defun bytecode-apply (xs)
+  (let ((n 0))
+    (mapcar (lambda (x) (bytecode-incf n) (bytecode-twice x)) xs)
+    n))
+(compile 'bytecode-apply)
+(assert-eq (bytecode-apply '(1 "a")) 2)
+(defun bytecode-twice-all (xs) (mapcar #'bytecode-twice xs))
+(compile 'bytecode-twice-all)
+(assert-eq (bytecode-twice-all '(1 "a")) '(2 "aa"))
+
+; the functions redefined later are called
+(defun bytecode-callee (x) (+ x 1))
+(defun bytecode-caller (x) (bytecode-callee x))
+(compile 'bytecode-caller)
+(assert-eq (bytecode-caller 1) 2)
+(defun bytecode-callee (x) (+ x 10))
+(assert-eq (bytecode-caller 1) 11)
+
+; the values made from the arguments are kept after the next call
+(defun bytecode-pair (a b) (vector a b))
+(compile 'bytecode-pair)
+(assert-eq (let ((v (bytecode-pair 1 2))) (bytecode-pair 3 4) v) #(1 2))
+
+; the local functions hide the global ones
+(defun bytecode-which () 'global)
+(flet ((bytecode-which () 'local))
+  (defun bytecode-local () (bytecode-which)))
+(compile 'bytecode-local)
+(assert-eq (list (bytecode-local) (bytecode-local)) '(local local))
+
+; the arithmetic of two arguments works for all the numbers
+(defun bytecode-arith (a b) (list (+ a b) (- a b) (* a b) (< a b) (>= a b) (= a b)))
+(compile 'bytecode-arith)
+(assert-eq (bytecode-arith 1 2) '(3 -1 2 t nil nil))
+(assert-eq (bytecode-arith 1.5 1.5) '(3.0 0.0 2.25 nil t t))
+(assert-eq (car (bytecode-arith 9223372036854775807 1)) 9223372036854775808)
+(assert-eq (catch 'c
+             (with-handler (lambda (c) (throw 'c (class-of c)))
+               (bytecode-arith 'a 1)))
+           (class <domain-error>))
@@ -0,0 +1,36 @@
+(defclass <cp-a> () ((a :initform 'a :initarg a :reader cp-a)))
+(defclass <cp-b> (<cp-a>) ((a :initform 'b)))
+(defclass <cp-c> (<cp-a>) ((c :initform 'c :reader cp-c)))
+(defclass <cp-d> (<cp-b> <cp-c>) ())
+
+(assert-eq (class-precedence-list (class <cp-d>))
+           (list (class <cp-d>) (class <cp-b>) (class <cp-c>) (class <cp-a>)
+                 (class <object>)))
+
+; the initform of the most specific class is used
+(assert-eq (cp-a (create (class <cp-d>))) 'b)
+(assert-eq (cp-c (create (class <cp-d>))) 'c)
+; the initarg of the super class is inherited
+(assert-eq (cp-a (create (class <cp-d>) 'a 'x)) 'x)
+
+(assert-eq (subclassp (class <cp-d>) (class <cp-a>)) t)
+(assert-eq (instancep (create (class <cp-d>)) (class <cp-c>)) t)
+
+; methods of the mixins are chosen by the class precedence list
+(defgeneric cp-which (x))
+(defmethod cp-which ((x <cp-c>)) 'c)
+(defmethod cp-which ((x <cp-b>)) 'b)
+(assert-eq (cp-which (create (class <cp-d>))) 'b)
+
+(defclass <cp-e> (<cp-c> <cp-b>) ())
+(assert-eq (cp-which (create (class <cp-e>))) 'c)
+
+; inconsistent orders can not be linearized
+(defclass <cp-x> () ())
+(defclass <cp-y> (<cp-x>) ())
+(assert-eq (ignore-errors (defclass <cp-z> (<cp-x> <cp-y>) ()) t) nil)
+
+(assert-eq (car (class-precedence-list (class <integer>))) (class <integer>))
+; the metaclass is not a super class of the built-in classes
+(assert-eq (member (class <built-in-class>) (class-precedence-list (class <integer>))) nil)
+(assert-eq (car (reverse (class-precedence-list (class <integer>)))) (class <object>))
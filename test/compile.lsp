@@ -0,0 +1,45 @@
+; the bodies of the functions are compiled at the first call
+
+; deep self tail calls through if, cond, let, let* and progn
+(defun count-down (n acc)
+  (cond
+    ((= n 0) acc)
+    (t (let ((m (- n 1)))
+         (let* ((a (+ acc 1)))
+           (progn (if t (count-down m a))))))))
+(assert-eq (count-down 100000 0) 100000)
+
+; the macro defined before the first call is expanded once
+(defmacro twice-of (x) (list '* 2 x))
+(defun use-twice (n) (twice-of n))
+(assert-eq (use-twice 4) 8)
+
+; the functions redefined later are called
+(defun compile-callee (x) (+ x 1))
+(defun compile-caller (x) (compile-callee x))
+(assert-eq (compile-caller 1) 2)
+(defun compile-callee (x) (+ x 10))
+(assert-eq (compile-caller 1) 11)
+
+; the special forms bound lexically are still the special forms
+(defun compile-case (x)
+  (case x
+    ((1 2) 'small)
+    ((3) (and (> x 2) (or nil 'three)))
+    (t 'other)))
+(assert-eq (compile-case 2) 'small)
+(assert-eq (compile-case 3) 'three)
+(assert-eq (compile-case 9) 'other)
+
+; while and setq
+(defun compile-sum (n)
+  (let ((i 0) (s 0))
+    (while (< i n)
+      (setq i (+ i 1))
+      (setq s (+ s i)))
+    s))
+(assert-eq (compile-sum 10) 55)
+
+; the closures see the variables of the outer function
+(defun compile-adder (n) (lambda (x) (+ x n)))
+(assert-eq (funcall (compile-adder 3) 4) 7)
@@ -0,0 +1,53 @@
+(defclass <in-a> () ((x :initarg x :accessor in-x :initform 0)))
+(defclass <in-b> (<in-a>) ((y :initarg y :reader in-y :boundp in-y-p)))
+
+(assert-eq (find-class '<in-b>) (class <in-b>))
+(assert-eq (find-class '<integer>) (class <integer>))
+(assert-eq (ignore-errors (find-class '<in-undefined>)) nil)
+(assert-eq (class-name (class <in-b>)) '<in-b>)
+(assert-eq (class-name (class <string>)) '<string>)
+(assert-eq (class-direct-superclasses (class <in-b>)) (list (class <in-a>)))
+(assert-eq (class-direct-superclasses (class <in-a>)) nil)
+
+(assert-eq (class-slots (class <in-b>))
+           '((x :initarg (x) :reader () :writer () :accessor (in-x) :boundp ())
+             (y :initarg (y) :reader (in-y) :writer () :accessor () :boundp (in-y-p))))
+(assert-eq (class-slots (class <integer>)) nil)
+
+(defgeneric in-area (shape &rest opts))
+(defmethod in-area ((s <in-a>) &rest (opts <object>)) 'a)
+(defmethod in-area :before ((s <in-b>) &rest (opts <symbol>)) nil)
+
+(let ((methods (generic-function-methods #'in-area)))
+  (assert-eq (length methods) 2)
+  (assert-eq (method-specializers (car methods))
+             (list (class <in-a>) :rest (class <object>)))
+  (assert-eq (method-qualifiers (car methods)) nil)
+  (assert-eq (method-qualifiers (cadr methods)) '(:before))
+  (assert-eq (instancep (car methods) (class <standard-method>)) t))
+(assert-eq (ignore-errors (generic-function-methods #'car)) nil)
+
+(let ((obj (create (class <in-b>) 'y 3)))
+  (assert-eq (slot-value obj 'x) 0)
+  (assert-eq (slot-value obj 'y) 3)
+  (assert-eq (slot-boundp obj 'y) t)
+  (set-slot-value 7 obj 'x)
+  (assert-eq (in-x obj) 7)
+  (setf (slot-value obj 'x) 8)
+  (assert-eq (slot-value obj 'x) 8)
+  (assert-eq (instancep obj (class <standard-object>)) t)
+  (assert-eq (slot-boundp (create (class <in-b>)) 'y) nil)
+  (assert-eq (ignore-errors (slot-value obj 'z)) nil))
+
+; slot-value signals an error for the unbound slot
+(assert-eq
+  (catch 'unbound
+    (with-handler
+      (lambda (c)
+        (if (and (instancep c <undefined-entity>)
+                 (eq (undefined-entity-name c) 'y)
+                 (eq (undefined-entity-namespace c) 'slot))
+          (throw 'unbound 'ok)
+          'ng))
+      (slot-value (create (class <in-b>)) 'y)))
+  'ok)
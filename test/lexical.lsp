@@ -0,0 +1,53 @@
+; the lexical variables of the compiled functions
+
+; the variables of the outer frames
+(defun lexical-nest (a)
+  (let ((b (+ a 1)))
+    (let ((c (+ b 1)) (a 10))
+      (list a b c))))
+(assert-eq (lexical-nest 1) '(10 2 3))
+
+; let* binds the variables one by one
+(defun lexical-let* (x)
+  (let* ((x (+ x 1)) (y (* x 2)) (x (+ y 1)))
+    (list x y)))
+(assert-eq (lexical-let* 1) '(5 4))
+
+; setq updates the variable of the frame
+(defun lexical-setq (n)
+  (let ((total 0))
+    (let ((i 0))
+      (while (< i n)
+        (setq i (+ i 1))
+        (setq total (+ total i))
+        (setq n n)))
+    total))
+(assert-eq (lexical-setq 4) 10)
+
+; the closures share the frames with the compiled code
+(defun lexical-counter ()
+  (let ((count 0))
+    (list
+      (lambda () (setq count (+ count 1)))
+      (lambda () count))))
+(let ((counter (lexical-counter)))
+  (funcall (car counter))
+  (funcall (car counter))
+  (assert-eq (funcall (car (cdr counter))) 2))
+
+; each call has its own frame
+(defun lexical-capture (n) (lambda () n))
+(let ((one (lexical-capture 1)) (two (lexical-capture 2)))
+  (assert-eq (list (funcall one) (funcall two)) '(1 2)))
+
+; the rest parameter and the variables after the slash
+(defun lexical-rest (a &rest b) (cons a b))
+(assert-eq (lexical-rest 1 2 3) '(1 2 3))
+(defun lexical-slash (a / b) (setq b (+ a 1)) b)
+(assert-eq (lexical-slash 1) 2)
+
+; the variables not bound lexically are global
+(defglobal lexical-global 1)
+(defun lexical-global-setq (v) (setq lexical-global v))
+(lexical-global-setq 5)
+(assert-eq lexical-global 5)
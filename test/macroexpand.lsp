@@ -1,4 +1,66 @@
-(defmacro dbl (x) (list '+ x x))
-(assert-eq 
-  (macroexpand '(dbl (incf a1)))
-  '(+ (incf a1) (incf a1)))
+(defmacro dbl (x) (list '+ x x))
+(assert-eq 
+  (macroexpand '(dbl (incf a1)))
+  '(+ (incf a1) (incf a1)))
+
+; macroexpand-all expands the subforms too
+(defmacro my-inc (x) (list 'setq x (list '+ x 1)))
+(assert-eq
+  (macroexpand-all '(let ((a (my-inc b))) (cond ((my-inc c) 'quoted '(my-inc d)))))
+  '(let ((a (setq b (+ b 1)))) (cond ((setq c (+ c 1)) 'quoted '(my-inc d)))))
+(assert-eq
+  (macroexpand-all '(lambda (my-inc) (list (my-inc my-inc))))
+  '(lambda (my-inc) (list (setq my-inc (+ my-inc 1)))))
+
+; the macros are expanded once in the body of each function
+(defglobal expand-count 0)
+(defmacro counted (x)
+  (setq expand-count (+ expand-count 1))
+  x)
+(defun use-counted (x) (counted x))
+(use-counted 1)
+(use-counted 2)
+(assert-eq expand-count 1)
+
+; and every time by the interpreter, which sees the form changed
+(defmacro twice (x) (list '* x 2))
+(defglobal twice-form (list 'twice 1))
+(assert-eq (eval twice-form) 2)
+(set-car 5 (cdr twice-form))
+(assert-eq (eval twice-form) 10)
+
+; and expanded again after they are redefined
+(defmacro counted (x) (list '* x 10))
+(assert-eq (use-counted 2) 20)
+(assert-eq (let ((s 0)) (dotimes (i 3) (setq s (+ s (counted i)))) s) 30)
+
+; the local functions hide the global macros
+(assert-eq
+  (macroexpand-all '(flet ((my-inc (x) (my-inc x))) (my-inc 1)))
+  '(flet ((my-inc (x) (setq x (+ x 1)))) (my-inc 1)))
+(assert-eq
+  (macroexpand-all '(labels ((my-inc (x) (my-inc x))) (my-inc 1)))
+  '(labels ((my-inc (x) (my-inc x))) (my-inc 1)))
+(assert-eq
+  (macroexpand-all '(flet ((f (x) x)) (flet ((g (y) y)) (my-inc a))))
+  '(flet ((f (x) x)) (flet ((g (y) y)) (setq a (+ a 1)))))
+
+; the variables of the special forms are not expanded
+(assert-eq
+  (macroexpand-all '(dynamic-let ((when (my-inc a))) (when 1 2)))
+  '(dynamic-let ((when (setq a (+ a 1)))) (if 1 (progn 2))))
+(assert-eq
+  (macroexpand-all '(with-open-input-file (when "x" (my-inc a)) (when 1 2)))
+  '(with-open-input-file (when "x" (setq a (+ a 1))) (if 1 (progn 2))))
+(assert-eq
+  (macroexpand-all '(defmethod f :around ((when <integer>)) (my-inc when)))
+  '(defmethod f :around ((when <integer>)) (setq when (+ when 1))))
+(assert-eq
+  (macroexpand-all '(defmethod f () (my-inc a)))
+  '(defmethod f () (setq a (+ a 1))))
+(assert-eq
+  (macroexpand-all '(restart-case (my-inc a) (when (x) (my-inc x))))
+  '(restart-case (setq a (+ a 1)) (when (x) (setq x (+ x 1)))))
+(assert-eq
+  (macroexpand-all '(defclass <c> () ((when :initform 1))))
+  '(defclass <c> () ((when :initform 1))))
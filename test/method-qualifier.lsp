@@ -0,0 +1,36 @@
+(defgeneric qualified (x))
+(defglobal qualified-log nil)
+(defun qualified-log (x) (setq qualified-log (cons x qualified-log)))
+
+(defmethod qualified ((x <integer>))
+  (qualified-log 'integer)
+  (call-next-method))
+(defmethod qualified (x)
+  (qualified-log 'object)
+  x)
+(defmethod qualified :before ((x <integer>))
+  (qualified-log 'before-integer))
+(defmethod qualified :before (x)
+  (qualified-log 'before-object))
+(defmethod qualified :after ((x <integer>))
+  (qualified-log 'after-integer))
+(defmethod qualified :after (x)
+  (qualified-log 'after-object))
+(defmethod qualified :around ((x <integer>))
+  (qualified-log 'around-integer)
+  (* (call-next-method) 10))
+
+(assert-eq (qualified 1) 10)
+(assert-eq (reverse qualified-log)
+           '(around-integer before-integer before-object integer object
+             after-object after-integer))
+
+(setq qualified-log nil)
+(assert-eq (qualified "a") "a")
+(assert-eq (reverse qualified-log) '(before-object object after-object))
+
+; :around method without call-next-method skips the others
+(defmethod qualified :around ((x <string>)) 'skipped)
+(setq qualified-log nil)
+(assert-eq (qualified "a") 'skipped)
+(assert-eq qualified-log nil)
@@ -0,0 +1,51 @@
+(defclass <node> () ())
+(defclass <text-node> (<node>) ())
+(defclass <bold-node> (<text-node>) ())
+
+(defgeneric describe-node (n))
+(defmethod describe-node ((n <bold-node>))
+  (if (next-method-p)
+    (cons 'bold (call-next-method))
+    'no-next))
+(defmethod describe-node ((n <node>))
+  (list 'node))
+(defmethod describe-node ((n <text-node>))
+  (cons 'text (call-next-method)))
+
+(assert-eq (describe-node (create (class <bold-node>))) '(bold text node))
+(assert-eq (describe-node (create (class <text-node>))) '(text node))
+(assert-eq (describe-node (create (class <node>))) '(node))
+
+; redefinition replaces the method with the same specializers
+(defmethod describe-node ((n <node>))
+  (list 'root))
+(assert-eq (describe-node (create (class <bold-node>))) '(bold text root))
+
+(defgeneric next-p (x))
+(defmethod next-p ((x <integer>)) (next-method-p))
+(defmethod next-p (x) 'object)
+(assert-eq (next-p 1) t)
+(assert-eq (next-p "a") 'object)
+
+(defgeneric specific (x y))
+(defmethod specific ((x <integer>) y) 'integer-object)
+(defmethod specific (x (y <integer>)) 'object-integer)
+(assert-eq (specific 1 2) 'integer-object)
+
+; the deep recursion of a generic function
+(defgeneric next-method-down (n))
+(defmethod next-method-down ((n <integer>))
+  (if (= n 0) 0 (+ 1 (next-method-down (- n 1)))))
+(assert-eq (next-method-down 20000) 20000)
+
+; the rest parameter is nil when no arguments are left
+(defgeneric next-method-rest (a &rest r))
+(defmethod next-method-rest ((a <object>) &rest (r <object>)) (list a r))
+(assert-eq (next-method-rest 1) '(1 nil))
+(assert-eq (next-method-rest 1 2 3) '(1 (2 3)))
+
+; the body sees the variables where the method is defined
+(let ((next-method-x 'defined))
+  (defmethod next-method-rest ((a <string>) &rest (r <object>)) next-method-x))
+(let ((next-method-x 'caller))
+  (assert-eq (next-method-rest "a") 'defined))
@@ -0,0 +1,41 @@
+(assert-eq (expt 2 10) 1024)
+(assert-eq (expt 2 100) 1267650600228229401496703205376)
+(assert-eq (expt 2.0 3) 8.0)
+(assert-eq (expt 2 -1) 1/2)
+(assert-eq (quotient 10 5) 2)
+(assert-eq (quotient 1 2) 0.5)
+(assert-eq (quotient 2 -0.5) -4.0)
+(assert-eq (quotient 60 2 3) 10)
+(assert-eq (reciprocal 2) 0.5)
+(assert-eq (isqrt 17) 4)
+(assert-eq (isqrt 100000000000000000000) 10000000000)
+(assert-eq (float 1) 1.0)
+(assert-eq (signum -5) -1)
+(assert-eq (signum 0) 0)
+(assert-eq (signum 2.5) 1.0)
+(assert-eq (integer-length 255) 8)
+(assert-eq (integer-length -256) 8)
+(assert-eq (abs -3) 3)
+(assert-eq (abs -2.5) 2.5)
+(assert-eq (abs -9223372036854775808) 9223372036854775808)
+(assert-eq (max 1 3 2) 3)
+(assert-eq (min 4 2 3) 2)
+(assert-eq (< (abs (- (atan2 1 1) (atan 1))) 1e-10) t)
+
+(defun arithmetic-error-class (thunk)
+  (with-handler
+    (lambda (c) (continue-condition c (class-of c)))
+    (funcall thunk)))
+
+(assert-eq (arithmetic-error-class (lambda () (exp 1000.0)))
+           (class <floating-point-overflow>))
+(assert-eq (arithmetic-error-class (lambda () (* 1e200 1e200)))
+           (class <floating-point-overflow>))
+(assert-eq (arithmetic-error-class (lambda () (* 1e-200 1e-200)))
+           (class <floating-point-underflow>))
+(assert-eq (arithmetic-error-class (lambda () (quotient 1 0)))
+           (class <division-by-zero>))
+(assert-eq (with-handler
+             (lambda (c) (continue-condition c (arithmetic-error-operands c)))
+             (atanh 2))
+           '(2))
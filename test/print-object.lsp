@@ -0,0 +1,24 @@
+(defclass <po-money> () ((amount :initarg amount :reader po-amount)))
+(defclass <po-yen> (<po-money>) ())
+(defclass <po-plain> () ((a :initarg a)))
+
+(defmethod print-object ((m <po-money>) stream)
+  (format stream "$~A" (po-amount m)))
+
+(assert-eq (format nil "~A" (create (class <po-money>) 'amount 10)) "$10")
+(assert-eq (format nil "~S" (create (class <po-money>) 'amount 10)) "$10")
+(assert-eq (format nil "~A" (list (create (class <po-money>) 'amount 5))) "($5)")
+(assert-eq (format-object nil (create (class <po-money>) 'amount 3) t) "$3")
+(assert-eq (format nil "~A" (create (class <po-plain>) 'a 1)) "<po-plain>{a:1}")
+
+; call-next-method reaches the method of the super class
+(defmethod print-object ((m <po-yen>) stream)
+  (call-next-method)
+  (format stream " (yen)"))
+(assert-eq (format nil "~A" (create (class <po-yen>) 'amount 7)) "$7 (yen)")
+
+; the default method prints any object
+(assert-eq (let ((s (create-string-output-stream)))
+             (print-object "abc" s)
+             (get-output-stream-string s))
+           "\"abc\"")
@@ -0,0 +1,26 @@
+(assert-eq (/ 1 3) 1/3)
+(assert-eq (/ 6 3) 2)
+(assert-eq (/ 2) 1/2)
+(assert-eq (/ 60 2 3) 10)
+(assert-eq (+ 1/3 2/3) 1)
+(assert-eq (- 1/2 1/3) 1/6)
+(assert-eq (* 1/3 3) 1)
+(assert-eq (/ 1/2 1/4) 2)
+(assert-eq (+ 1/2 0.25) 0.75)
+(assert-eq (+ 1 1/2) 3/2)
+(assert-eq (* 100000000000000000000 1/4) 25000000000000000000)
+(assert-eq (< 1/3 1/2) t)
+(assert-eq (< 1/3 0.3) nil)
+(assert-eq (> 1 1/2) t)
+(assert-eq (= 2/4 1/2) t)
+(assert-eq (format nil "~A ~D" 1/3 -2/3) "1/3 -2/3")
+(assert-eq (format nil "~F" 1/4) "0.25")
+(assert-eq (floor 7/2) 3)
+(assert-eq (ceiling 7/2) 4)
+(assert-eq (truncate -7/2) -3)
+(assert-eq (round -7/2) -4)
+(assert-eq (expt 2/3 2) 4/9)
+(assert-eq (abs -1/3) 1/3)
+(assert-eq (convert 1/4 <float>) 0.25)
+(assert-eq (instancep 1/3 (class <ratio>)) t)
+(assert-eq (numberp 1/3) t)
@@ -0,0 +1,79 @@
+(defun rs-parse (r)
+  (restart-case
+    (if (numberp r) r (error "bad record ~A" r))
+    (skip-record () 'skipped)
+    (use-value (v) v)))
+
+(assert-eq (with-handler
+             (lambda (c) (invoke-restart 'skip-record))
+             (mapcar #'rs-parse '(1 x 3)))
+           '(1 skipped 3))
+(assert-eq (with-handler
+             (lambda (c) (invoke-restart 'use-value 0))
+             (mapcar #'rs-parse '(1 x 3)))
+           '(1 0 3))
+(assert-eq (rs-parse 5) 5)
+
+; compute-restarts returns the restarts from the innermost one
+(assert-eq (mapcar #'restart-name
+                   (restart-case
+                     (restart-case (compute-restarts) (a () 1) (b () 2))
+                     (c () 3)))
+           '(a b c))
+(assert-eq (compute-restarts) nil)
+(assert-eq (restart-case (restart-name (find-restart 'a)) (a () 1)) 'a)
+(assert-eq (find-restart 'a) nil)
+(assert-eq (restart-case (invoke-restart (find-restart 'a) 10) (a (x) (+ x 1))) 11)
+(assert-eq (ignore-errors (invoke-restart 'no-such-restart)) nil)
+
+; retry
+(let ((n 0))
+  (assert-eq
+    (block done
+      (tagbody
+        again
+        (return-from done
+          (with-handler
+            (lambda (c) (invoke-restart 'retry))
+            (restart-case
+              (progn (setq n (+ n 1)) (if (< n 3) (error "fail") n))
+              (retry () (go again)))))))
+    3))
+
+; the handler is called where the condition is signaled
+(let ((state 'before))
+  (assert-eq
+    (catch 'c
+      (with-handler
+        (lambda (c) (throw 'c state))
+        (unwind-protect
+          (progn (setq state 'signaled) (error "x"))
+          (setq state 'unwound))))
+    'signaled))
+
+; the handler runs with only the outer handlers active
+(assert-eq
+  (catch 'c
+    (with-handler
+      (lambda (c) (throw 'c 'outer))
+      (with-handler
+        (lambda (c) (error "error in the handler"))
+        (error "x"))))
+  'outer)
+
+; continue-condition returns from signal-condition for continuable conditions
+(assert-eq
+  (with-handler
+    (lambda (c) (continue-condition c 10))
+    (+ (cerror "use a value" "not a number") 1))
+  11)
+(assert-eq
+  (catch 'c
+    (with-handler
+      (lambda (c)
+        (if (instancep c (class <control-error>))
+          (throw 'c 'not-continuable)))
+      (with-handler
+        (lambda (c) (continue-condition c 10))
+        (error "not continuable"))))
+  'not-continuable)
@@ -0,0 +1,35 @@
+; the calls at the tail position do not grow the stack
+
+; mutual recursion of labels
+(assert-eq
+  (labels ((ev (n) (if (= n 0) t (od (- n 1))))
+           (od (n) (if (= n 0) nil (ev (- n 1)))))
+    (ev 100001))
+  nil)
+
+; mutual recursion of defun through cond, case, let*, and, or
+(defun tail-ping (n acc)
+  (cond
+    ((= n 0) acc)
+    (t (let* ((m (- n 1)))
+         (and t (tail-pong m (+ acc 1)))))))
+(defun tail-pong (n acc)
+  (case (mod n 2)
+    ((0 1) (progn (or nil (tail-ping n acc))))))
+(assert-eq (tail-ping 100000 0) 100000)
+
+; the state machine by funcall of closures
+(defun tail-machine (n)
+  (let ((state-a nil) (state-b nil))
+    (setq state-a (lambda (i) (if (= i 0) 'a (funcall state-b (- i 1)))))
+    (setq state-b (lambda (i) (if (= i 0) 'b (funcall state-a (- i 1)))))
+    (funcall state-a n)))
+(assert-eq (tail-machine 100000) 'a)
+(assert-eq (tail-machine 100001) 'b)
+
+; return-from the block outside the tail calls
+(defun tail-return (n)
+  (block found
+    (labels ((walk (i) (if (= i n) (return-from found i) (walk (+ i 1)))))
+      (walk 0))))
+(assert-eq (tail-return 10) 10)
@@ -19,6 +19,7 @@ var (
 	ErrIndexOutOfRange = errors.New("index out of range")
 	ErrInvalidFormat   = errors.New("invalid format")
 	ErrNoMatchMethods  = errors.New("no match methods")
+	ErrNoNextMethod    = errors.New("no next method")
 	ErrNotSupportType  = errors.New("not support type")
 	ErrQuit            = errors.New("bye")
 
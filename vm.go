@@ -0,0 +1,437 @@
+package gmnlisp
+
+import (
+	"context"
+	"fmt"
+)
+
+// SetAutoCompile makes defun compile the functions into the bytecode as
+// (compile) does when on is true.
+func (w *World) SetAutoCompile(on bool) {
+	w.autoCompile = on
+}
+
+// compileLambda replaces the body of L with the bytecode.
+func compileLambda(ctx context.Context, L *_Lambda) *_Program {
+	if L.program == nil {
+		L.program = assemble(ctx, L)
+		L.body = L.program.run
+	}
+	return L.program
+}
+
+// lambdaOf returns the lambda which the function named symbol is.
+func lambdaOf(ctx context.Context, w *World, arg Node) (*_Lambda, error) {
+	symbol, err := ExpectSymbol(ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	f, err := w.GetFunc(symbol)
+	if err != nil {
+		return nil, err
+	}
+	L, ok := f.(*_Lambda)
+	if !ok {
+		_, err := raiseError(ctx, w, fmt.Errorf("%s: not a function defined by defun", symbol.String()))
+		return nil, err
+	}
+	return L, nil
+}
+
+func funCompile(ctx context.Context, w *World, arg Node) (Node, error) {
+	L, err := lambdaOf(ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	compileLambda(ctx, L)
+	return arg, nil
+}
+
+func funDisassemble(ctx context.Context, w *World, arg Node) (Node, error) {
+	L, err := lambdaOf(ctx, w, arg)
+	if err != nil {
+		return nil, err
+	}
+	// the function not compiled yet is assembled only to be shown.
+	p := L.program
+	if p == nil {
+		p = assemble(ctx, L)
+	}
+	p.disassemble(w.stdout, "")
+	return Null, nil
+}
+
+// resolveCallable returns the function which the embedded script f
+// defines, or f itself.
+func resolveCallable(ctx context.Context, w *World, f Callable) (Callable, error) {
+	if ls, ok := f.(*LispString); ok {
+		value, err := ls.Eval(ctx, w)
+		if err != nil {
+			return nil, err
+		}
+		if ref, ok := value.(FunctionRef); ok {
+			return ref.value, nil
+		}
+	}
+	return f, nil
+}
+
+// evaluatesArguments returns true when f is given the values of the
+// arguments. The other Callables such as the special forms and the macros
+// are given the source of the arguments.
+func evaluatesArguments(f Callable) bool {
+	switch f.(type) {
+	case Function0, Function1, Function2, *Function, *_Lambda, *_Generic:
+		return true
+	}
+	return false
+}
+
+// callValues calls f with the values of the arguments. values may be
+// overwritten after it returns, but not while f is running. When tail is
+// true and f is a lambda not traced, it returns _ErrTailRecOpt instead of
+// calling f.
+func callValues(ctx context.Context, w *World, f Callable, values []Node, tail bool) (Node, error) {
+	switch fn := f.(type) {
+	case Function0:
+		if len(values) == 0 {
+			return fn(ctx, w)
+		}
+	case Function1:
+		if len(values) == 1 {
+			return fn(ctx, w, values[0])
+		}
+	case Function2:
+		if len(values) == 2 {
+			return fn(ctx, w, values[0], values[1])
+		}
+	case *Function:
+		if min, max := fn.bounds(); min <= len(values) && len(values) <= max {
+			if err := checkContext(ctx); err != nil {
+				return nil, err
+			}
+			return fn.F(ctx, w, append([]Node(nil), values...))
+		}
+	case *_Lambda:
+		if err := checkContext(ctx); err != nil {
+			return nil, err
+		}
+		if tail && !traced(w, fn) {
+			return nil, &_ErrTailRecOpt{callee: fn, args: append([]Node(nil), values...)}
+		}
+		return fn.apply(ctx, w, values)
+	}
+	return f.Call(ctx, w, UnevalList(values...))
+}
+
+// _CallSite is the function found by an opFunction. It is used again while
+// no global function is defined and no local function may hide it.
+type _CallSite struct {
+	f         Callable
+	callee    Callable
+	evaluates bool
+	version   uint64
+}
+
+// function returns the function of the form source, which the opFunction
+// at pc calls. global is true when w is not in flet and the like.
+func (p *_Program) function(ctx context.Context, w *World, pc int, source *Cons, global bool) (_CallSite, error) {
+	site := &p.sites[pc]
+	if site.f != nil && site.version == w.funcVersion && global {
+		return *site, nil
+	}
+	symbol := source.Car.(Symbol)
+	f, err := w.GetFunc(symbol)
+	if err != nil {
+		return _CallSite{}, callError(err, symbol, source.pos)
+	}
+	callee, err := resolveCallable(ctx, w, f)
+	if err != nil {
+		return _CallSite{}, err
+	}
+	found := _CallSite{
+		f:         f,
+		callee:    callee,
+		evaluates: evaluatesArguments(callee),
+		version:   w.funcVersion,
+	}
+	if global {
+		*site = found
+	}
+	return found, nil
+}
+
+// inFlet returns true when w has the local functions such as the ones of
+// flet and labels.
+func inFlet(w *World) bool {
+	for ; w.parent != nil; w = w.parent {
+		if w.funcs != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// traced returns true when the calls of L are traced by (trace).
+func traced(w *World, L *_Lambda) bool {
+	if len(w.trace) == 0 {
+		return false
+	}
+	_, ok := w.trace[L.name]
+	return ok
+}
+
+// callAt does what Cons.Eval does around the call of the form source.
+func callAt(ctx context.Context, w *World, source *Cons, call func() (Node, error)) (Node, error) {
+	save, err := enterCall(ctx, w, source)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := call()
+	return rc, leaveCall(w, source, save, err)
+}
+
+// enterCall starts the call of the form source and returns the call site
+// which has to be given to leaveCall.
+func enterCall(ctx context.Context, w *World, source *Cons) (*Position, error) {
+	if w.limited {
+		if err := w.enterEval(ctx); err != nil {
+			return nil, err
+		}
+	}
+	save := w.callSite
+	if source.pos != nil {
+		w.callSite = source.pos
+	}
+	return save, nil
+}
+
+func leaveCall(w *World, source *Cons, save *Position, err error) error {
+	w.callSite = save
+	if w.limited {
+		w.leaveEval()
+	}
+	if err != nil {
+		if _, ok := err.(*_ErrTailRecOpt); ok {
+			return err
+		}
+		symbol, _ := source.Car.(Symbol)
+		return callError(err, symbol, source.pos)
+	}
+	return nil
+}
+
+// binary replaces the two values on the top of the stack with the result
+// of op, doing what enterCall and leaveCall do around it.
+func (p *_Program) binary(ctx context.Context, w *World, s *_Stack, source *Cons, op *_BinaryOp, limited bool) error {
+	if limited {
+		if err := w.step(ctx); err != nil {
+			return err
+		}
+	}
+	save := w.callSite
+	if source.pos != nil {
+		w.callSite = source.pos
+	}
+	n := len(s.values)
+	value, err := op.apply(ctx, w, s.values[n-2], s.values[n-1])
+	w.callSite = save
+	if err != nil {
+		symbol, _ := source.Car.(Symbol)
+		return callError(err, symbol, source.pos)
+	}
+	if op.test {
+		value, _ = notNullToTrue(value, nil)
+	}
+	s.values[n-2] = value
+	s.values[n-1] = nil
+	s.values = s.values[:n-1]
+	return nil
+}
+
+// _Stack is the stack of the values shared by the programs running in
+// a World. Each program uses it above the values of its callers.
+type _Stack struct {
+	values []Node
+	// functions are the functions whose arguments are on the stack.
+	functions []Callable
+}
+
+func (s *_Stack) pop() Node {
+	top := s.values[len(s.values)-1]
+	s.values = s.values[:len(s.values)-1]
+	return top
+}
+
+// run executes p with w whose frame has the parameters of the lambda.
+func (p *_Program) run(ctx context.Context, w *World) (Node, error) {
+	s := &w.stack
+	sp, fp := len(s.values), len(s.functions)
+	value, err := p.exec(ctx, w, s)
+	for i := sp; i < len(s.values); i++ {
+		s.values[i] = nil
+	}
+	s.values = s.values[:sp]
+	s.functions = s.functions[:fp]
+	return value, err
+}
+
+func (p *_Program) exec(ctx context.Context, w *World, s *_Stack) (Node, error) {
+	limited := w.limited
+	// the functions found out of flet are cached by the call sites.
+	// The frames made by the program have no functions.
+	global := !inFlet(w)
+	for pc := 0; ; {
+		inst := p.code[pc]
+		pc++
+		if limited && countsStep[inst.op] {
+			if err := w.step(ctx); err != nil {
+				return nil, err
+			}
+		}
+		switch inst.op {
+		case opConst:
+			s.values = append(s.values, p.constants[inst.a])
+		case opLocal:
+			s.values = append(s.values, frameOf(w, int(inst.a)).values[inst.b])
+		case opSetLocal:
+			frameOf(w, int(inst.a)).values[inst.b] = s.values[len(s.values)-1]
+		case opGlobal:
+			value, err := w.Get(p.constants[inst.a].(Symbol))
+			if err != nil {
+				return nil, err
+			}
+			s.values = append(s.values, value)
+		case opSetGlobal:
+			if err := w.Set(p.constants[inst.a].(Symbol), s.values[len(s.values)-1]); err != nil {
+				return nil, err
+			}
+		case opPop:
+			s.pop()
+		case opJump:
+			if int(inst.a) < pc {
+				if err := checkContext(ctx); err != nil {
+					return nil, err
+				}
+			}
+			pc = int(inst.a)
+		case opJumpIfNil:
+			if IsNone(s.pop()) {
+				pc = int(inst.a)
+			}
+		case opJumpIfNilOrPop:
+			if IsNone(s.values[len(s.values)-1]) {
+				s.values[len(s.values)-1] = Null
+				pc = int(inst.a)
+			} else {
+				s.pop()
+			}
+		case opJumpIfSomeOrPop:
+			if IsSome(s.values[len(s.values)-1]) {
+				pc = int(inst.a)
+			} else {
+				s.pop()
+			}
+		case opFunction:
+			source := p.constants[inst.a].(*Cons)
+			site, err := p.function(ctx, w, pc-1, source, global)
+			if err != nil {
+				return nil, err
+			}
+			if site.evaluates {
+				s.functions = append(s.functions, site.callee)
+				break
+			}
+			value, err := callAt(ctx, w, source, func() (Node, error) {
+				return site.f.Call(ctx, w, source.Cdr)
+			})
+			if err != nil {
+				return nil, err
+			}
+			s.values = append(s.values, value)
+			pc = int(inst.b)
+		case opCallable:
+			f, err := ExpectFunction(ctx, w, s.values[len(s.values)-1])
+			if err != nil {
+				return nil, err
+			}
+			if f, err = resolveCallable(ctx, w, f); err != nil {
+				return nil, err
+			}
+			s.pop()
+			s.functions = append(s.functions, f)
+		case opCall, opTailCall, opBinary:
+			argc := int(inst.b)
+			f := s.functions[len(s.functions)-1]
+			s.functions = s.functions[:len(s.functions)-1]
+			source := p.constants[inst.a].(*Cons)
+			if inst.op == opBinary {
+				argc = 2
+				if op := &binaryOps[inst.b]; f == op.function {
+					if err := p.binary(ctx, w, s, source, op, limited); err != nil {
+						return nil, err
+					}
+					break
+				}
+			}
+			base := len(s.values) - argc
+			save, err := enterCall(ctx, w, source)
+			if err != nil {
+				return nil, err
+			}
+			value, err := callValues(ctx, w, f, s.values[base:], inst.op == opTailCall)
+			if err = leaveCall(w, source, save, err); err != nil {
+				return nil, err
+			}
+			s.values = append(s.values[:base], value)
+		case opMacro:
+			source := p.constants[inst.a].(*Cons)
+			f := p.constants[inst.a+1].(FunctionRef).value
+			if g, err := w.GetFunc(source.Car.(Symbol)); err != nil || g != f {
+				pc = int(inst.b)
+			}
+		case opInterpret:
+			value, err := w.Eval(ctx, p.constants[inst.a])
+			if err != nil {
+				return nil, err
+			}
+			s.values = append(s.values, value)
+		case opLet:
+			names := p.frames[inst.a]
+			base := len(s.values) - len(names)
+			values := make([]Node, len(names))
+			copy(values, s.values[base:])
+			s.values = s.values[:base]
+			w = w.Let(&_Frame{names: names, values: values})
+		case opLetX:
+			w = w.Let(newFrame(p.frames[inst.a]))
+		case opBind:
+			frame := w.vars.(*_Frame)
+			frame.values = append(frame.values, s.pop())
+		case opLeave:
+			w = w.parent
+		case opCase:
+			key := s.values[len(s.values)-1]
+			matched := false
+			for keys := p.constants[inst.a]; IsSome(keys) && !matched; {
+				cons := keys.(*Cons)
+				matched = key.Equals(cons.Car, EQUALP)
+				keys = cons.Cdr
+			}
+			if matched {
+				s.pop()
+			} else {
+				pc = int(inst.b)
+			}
+		case opBlock:
+			block := p.blocks[inst.b]
+			value, err := inBlock(ctx, w, p.constants[inst.a].(Symbol), block.run)
+			if err != nil {
+				return nil, err
+			}
+			s.values = append(s.values, value)
+		case opReturn:
+			return s.values[len(s.values)-1], nil
+		}
+	}
+}
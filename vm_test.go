@@ -0,0 +1,73 @@
+package gmnlisp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	w := New()
+	w.SetAutoCompile(true)
+	var out strings.Builder
+	w.SetStdout(&out)
+	value, err := w.Interpret(context.TODO(), `
+		(defgeneric compile-area (s))
+		(defmethod compile-area ((s <integer>)) (* s s))
+		(defun compile-total (xs)
+			(let ((total 0))
+				(dolist (x xs)
+					(setq total (+ total (compile-area x))))
+				total))
+		(disassemble 'compile-total)
+		(compile-total '(1 2 3))`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if !value.Equals(Integer(14), EQUAL) {
+		t.Fatalf("got %v", value)
+	}
+	if listing := out.String(); !strings.Contains(listing, "block") || !strings.Contains(listing, "compile-area") {
+		t.Fatalf("disassemble printed %q", listing)
+	}
+
+	// disassemble does not compile the function
+	w.SetAutoCompile(false)
+	if _, err := w.Interpret(context.TODO(), `
+		(defun compile-square (x) (* x x))
+		(disassemble 'compile-square)`); err != nil {
+		t.Fatal(err.Error())
+	}
+	f, err := w.GetFunc(NewSymbol("compile-square"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if f.(*_Lambda).program != nil {
+		t.Fatal("disassemble compiled the function")
+	}
+}
+
+func BenchmarkCompile(b *testing.B) {
+	const fib = `(defun fib (n) (if (< n 2) n (+ (fib (- n 1)) (fib (- n 2)))))`
+	for _, mode := range []struct {
+		name string
+		code string
+	}{
+		{name: "closure", code: fib},
+		{name: "bytecode", code: fib + `(compile 'fib)`},
+	} {
+		b.Run(mode.name, func(b *testing.B) {
+			ctx := context.TODO()
+			w := New()
+			if _, err := w.Interpret(ctx, mode.code); err != nil {
+				b.Fatal(err.Error())
+			}
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := w.Interpret(ctx, `(fib 15)`); err != nil {
+					b.Fatal(err.Error())
+				}
+			}
+		})
+	}
+}
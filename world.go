@@ -8,6 +8,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"math"
 	"os"
 	"strings"
@@ -121,6 +122,7 @@ type Writer interface {
 type shared struct {
 	macro     map[Symbol]*_Macro
 	handler   []Callable
+	restarts  []*_Restart
 	global    Scope
 	defun     FuncScope
 	dynamic   Variables
@@ -141,8 +143,31 @@ type shared struct {
 	blockName map[Symbol]struct{}
 	catchTag  map[Node]struct{}
 	goTag     map[Symbol]struct{}
-}
-
+	frames    []Frame
+	trace     map[Symbol]int
+	denied    Capability
+	fsys      fs.FS
+	limits    Limits
+	// limited is true when limits has any quota.
+	limited  bool
+	used     _Usage
+	callSite *Position
+	// autoCompile makes defun compile the functions into the bytecode.
+	autoCompile bool
+	// stack is the stack of the bytecode programs running.
+	stack _Stack
+	// funcVersion is incremented whenever a global function is defined,
+	// so that the bytecode programs can tell their cached callees are old.
+	funcVersion uint64
+}
+
+// World is an instance of the interpreter. A World must not be used by
+// multiple goroutines at the same time, but different Worlds may run in
+// parallel goroutines. Symbols, keywords, classes of the builtin types and
+// the immutable values such as numbers and strings can be shared between
+// Worlds. The mutable objects such as conses, arrays, hash tables, instances
+// of defclass, functions and streams belong to the World which made them
+// and must not be passed to other Worlds running concurrently.
 type World struct {
 	*shared
 	parent *World
@@ -214,6 +239,7 @@ func (w *World) SetStdout(writer io.Writer) {
 	} else {
 		w.stdout = &_WriterNode{_Writer: writer}
 	}
+	w.stdout = w.limitOutput(w.stdout)
 }
 
 func funErrorOutput(ctx context.Context, w *World) (Node, error) {
@@ -230,6 +256,7 @@ func (w *World) SetErrout(writer io.Writer) {
 	} else {
 		w.errout = &_WriterNode{_Writer: writer}
 	}
+	w.errout = w.limitOutput(w.errout)
 }
 
 func funStandardInput(ctx context.Context, w *World) (Node, error) {
@@ -252,6 +279,7 @@ var autoLoadConstants = Constants{
 	NewSymbol("*err-too-short-tokens*"):   ErrorNode{Value: ErrTooShortTokens},
 	NewSymbol("*most-negative-float*"):    Float(-math.MaxFloat64),
 	NewSymbol("*most-positive-float*"):    Float(math.MaxFloat64),
+	NewSymbol("*pi*"):                     Float(math.Pi),
 	NewSymbol("<error>"):                  errorClass,
 	NewSymbol("most-negative-fixnum"):     Integer(math.MinInt),
 	NewSymbol("most-positive-fixnum"):     Integer(math.MaxInt),
@@ -268,6 +296,7 @@ var autoLoadFunc = Functions{
 	NewSymbol("*"):                              &Function{F: funMulti},
 	NewSymbol("+"):                              &Function{F: funAdd},
 	NewSymbol("-"):                              &Function{F: funSub},
+	NewSymbol("/"):                              &Function{Min: 1, F: funDevide},
 	NewSymbol("/="):                             &Function{C: 2, F: funNotEqual},
 	NewSymbol("<"):                              &Function{F: funLessThan},
 	NewSymbol("<="):                             &Function{F: funLessOrEqual},
@@ -275,6 +304,7 @@ var autoLoadFunc = Functions{
 	NewSymbol(">"):                              &Function{F: funGreaterThan},
 	NewSymbol(">="):                             &Function{F: funGreaterOrEqual},
 	NewSymbol("abort"):                          Function0(funAbort),
+	NewSymbol("abs"):                            Function1(funAbs),
 	NewSymbol("and"):                            SpecialF(cmdAnd),
 	NewSymbol("append"):                         &Function{F: funAppend},
 	NewSymbol("apply"):                          SpecialF(cmdApply),
@@ -285,7 +315,10 @@ var autoLoadFunc = Functions{
 	NewSymbol("assoc"):                          Function2(Assoc),
 	NewSymbol("assure"):                         Function2(funAssure),
 	NewSymbol("atan"):                           funMath1(math.Atan),
+	NewSymbol("atan2"):                          Function2(funAtan2),
+	NewSymbol("atanh"):                          funMath1(math.Atanh),
 	NewSymbol("atom"):                           Function1(funAtom),
+	NewSymbol("backtrace"):                      Function0(funBacktrace),
 	NewSymbol("basic-array*-p"):                 Function1(funGeneralArray),
 	NewSymbol("basic-array-p"):                  Function1(funBasicArray),
 	NewSymbol("block"):                          SpecialF(cmdBlock),
@@ -303,9 +336,15 @@ var autoLoadFunc = Functions{
 	NewSymbol("char>="):                         &Function{C: 2, F: funRuneGe},
 	NewSymbol("characterp"):                     Function1(funAnyTypep[Rune]),
 	NewSymbol("class"):                          SpecialF(cmdClass),
+	NewSymbol("class-direct-superclasses"):      Function1(funClassDirectSuperclasses),
+	NewSymbol("class-name"):                     Function1(funClassName),
 	NewSymbol("class-of"):                       Function1(funClassOf),
+	NewSymbol("class-precedence-list"):          Function1(funClassPrecedenceList),
+	NewSymbol("class-slots"):                    Function1(funClassSlots),
 	NewSymbol("close"):                          Function1(funClose),
 	NewSymbol("clrhash"):                        Function1(funClearHash),
+	NewSymbol("compile"):                        Function1(funCompile),
+	NewSymbol("compute-restarts"):               &Function{Max: 1, F: funComputeRestarts},
 	NewSymbol("cond"):                           SpecialF(cmdCond),
 	NewSymbol("cons"):                           Function2(funCons),
 	NewSymbol("consp"):                          Function1(funAnyTypep[*Cons]),
@@ -327,7 +366,8 @@ var autoLoadFunc = Functions{
 	NewSymbol("defmacro"):                       SpecialF(cmdDefMacro),
 	NewSymbol("defmethod"):                      SpecialF(cmdDefMethod),
 	NewSymbol("defun"):                          SpecialF(cmdDefun),
-	NewSymbol("div"):                            &Function{C: 2, F: funDevide},
+	NewSymbol("disassemble"):                    Function1(funDisassemble),
+	NewSymbol("div"):                            Function2(funDiv),
 	NewSymbol("domain-error-expected-class"):    Function1(funDomainErrorExpectedClass),
 	NewSymbol("domain-error-object"):            Function1(funDomainErrorObject),
 	NewSymbol("dynamic"):                        SpecialF(cmdDynamic),
@@ -343,9 +383,13 @@ var autoLoadFunc = Functions{
 	NewSymbol("exit"):                           Function0(funQuit),
 	NewSymbol("exp"):                            funMath1(math.Exp),
 	NewSymbol("expand-defun"):                   SpecialF(cmdExpandDefun),
+	NewSymbol("expt"):                           Function2(funExpt),
 	NewSymbol("file-length"):                    Function2(funFileLength),
 	NewSymbol("file-position"):                  Function1(funFilePosition),
+	NewSymbol("find-class"):                     Function1(funFindClass),
+	NewSymbol("find-restart"):                   Function1(funFindRestart),
 	NewSymbol("flet"):                           SpecialF(cmdFlet),
+	NewSymbol("float"):                          Function1(funFloat),
 	NewSymbol("floatp"):                         Function1(funAnyTypep[Float]),
 	NewSymbol("floor"):                          Function1(funFloor),
 	NewSymbol("format"):                         &Function{Min: 2, F: funFormat},
@@ -358,7 +402,9 @@ var autoLoadFunc = Functions{
 	NewSymbol("funcall"):                        SpecialF(cmdFunCall),
 	NewSymbol("function"):                       SpecialF(cmdFunction),
 	NewSymbol("functionp"):                      Function1(funAnyTypep[FunctionRef]),
+	NewSymbol("gcd"):                            Function2(funGcd),
 	NewSymbol("general-array*-p"):               Function1(funGeneralArray),
+	NewSymbol("generic-function-methods"):       Function1(funGenericFunctionMethods),
 	NewSymbol("generic-function-p"):             Function1(funGenericFunctionP),
 	NewSymbol("gensym"):                         Function0(funGensym),
 	NewSymbol("get-internal-real-time"):         Function0(funInternalRealTime),
@@ -374,12 +420,16 @@ var autoLoadFunc = Functions{
 	NewSymbol("ignore-errors"):                  SpecialF(cmdIgnoreErrors),
 	NewSymbol("input-stream-p"):                 Function1(funInputStreamP),
 	NewSymbol("instancep"):                      SpecialF(defInstanceP),
-	NewSymbol("integerp"):                       Function1(funAnyTypep[Integer]),
+	NewSymbol("integer-length"):                 Function1(funIntegerLength),
+	NewSymbol("integerp"):                       Function1(funIntegerp),
 	NewSymbol("internal-time-units-per-second"): Function0(funInternalTimeUnitPerSecond),
+	NewSymbol("invoke-restart"):                 &Function{Min: 1, F: funInvokeRestart},
+	NewSymbol("isqrt"):                          Function1(funIsqrt),
 	NewSymbol("labels"):                         SpecialF(cmdLabels),
 	NewSymbol("lambda"):                         SpecialF(cmdLambda),
 	NewSymbol("lambda-macro"):                   SpecialF(cmdLambdaMacro),
 	NewSymbol("last"):                           Function1(funLast),
+	NewSymbol("lcm"):                            Function2(funLcm),
 	NewSymbol("length"):                         Function1(funLength),
 	NewSymbol("let"):                            SpecialF(cmdLet),
 	NewSymbol("let*"):                           SpecialF(cmdLetX),
@@ -388,6 +438,7 @@ var autoLoadFunc = Functions{
 	NewSymbol("load"):                           Function1(funLoad),
 	NewSymbol("log"):                            Function1(funLog),
 	NewSymbol("macroexpand"):                    Function1(funMacroExpand),
+	NewSymbol("macroexpand-all"):                Function1(funMacroExpandAll),
 	NewSymbol("make-hash-table"):                Function0(funMakeHashTable),
 	NewSymbol("mapc"):                           &Function{F: funMapC},
 	NewSymbol("mapcan"):                         &Function{F: funMapCan},
@@ -395,7 +446,11 @@ var autoLoadFunc = Functions{
 	NewSymbol("mapcon"):                         &Function{F: funMapCon},
 	NewSymbol("mapl"):                           &Function{F: funMapL},
 	NewSymbol("maplist"):                        &Function{F: funMapList},
+	NewSymbol("max"):                            &Function{Min: 1, F: funMax},
 	NewSymbol("member"):                         Function2(funMember),
+	NewSymbol("method-qualifiers"):              Function1(funMethodQualifiers),
+	NewSymbol("method-specializers"):            Function1(funMethodSpecializers),
+	NewSymbol("min"):                            &Function{Min: 1, F: funMin},
 	NewSymbol("minusp"):                         Function1(funMinusp),
 	NewSymbol("mod"):                            Function2(funMod),
 	NewSymbol("not"):                            Function1(funNot),
@@ -420,13 +475,17 @@ var autoLoadFunc = Functions{
 	NewSymbol("quasiquote"):                     SpecialF(cmdQuasiQuote),
 	NewSymbol("quit"):                           Function0(funQuit),
 	NewSymbol("quote"):                          SpecialF(cmdQuote),
+	NewSymbol("quotient"):                       &Function{Min: 2, F: funQuotient},
 	NewSymbol("read"):                           &Function{Max: 3, F: funRead},
 	NewSymbol("read-byte"):                      &Function{Min: 1, Max: 3, F: funReadByte},
 	NewSymbol("read-char"):                      &Function{Max: 3, F: funReadChar},
 	NewSymbol("read-line"):                      &Function{Max: 3, F: funReadLine},
+	NewSymbol("reciprocal"):                     Function1(funReciprocal),
 	NewSymbol("rem"):                            Function2(funRem),
 	NewSymbol("remhash"):                        Function2(funRemoveHash),
 	NewSymbol("rest"):                           Function1(funGetCdr),
+	NewSymbol("restart-case"):                   SpecialF(cmdRestartCase),
+	NewSymbol("restart-name"):                   Function1(funRestartName),
 	NewSymbol("return"):                         Function1(funReturn),
 	NewSymbol("return-from"):                    SpecialF(cmdReturnFrom),
 	NewSymbol("reverse"):                        Function1(funReverse),
@@ -436,10 +495,14 @@ var autoLoadFunc = Functions{
 	NewSymbol("set-cdr"):                        Function2(funSetCdr),
 	NewSymbol("set-file-position"):              Function2(funSetFilePosition),
 	NewSymbol("set-gethash"):                    &Function{C: 3, F: funSetHash},
+	NewSymbol("set-slot-value"):                 &Function{C: 3, F: funSetSlotValue},
 	NewSymbol("setq"):                           SpecialF(cmdSetq),
 	NewSymbol("signal-condition"):               Function2(funSignalCondition),
+	NewSymbol("signum"):                         Function1(funSignum),
 	NewSymbol("sin"):                            funMath1(math.Sin),
 	NewSymbol("sinh"):                           funMath1(math.Sinh),
+	NewSymbol("slot-boundp"):                    Function2(funSlotBoundp),
+	NewSymbol("slot-value"):                     Function2(funSlotValue),
 	NewSymbol("sqrt"):                           Function1(funSqrt),
 	NewSymbol("standard-input"):                 Function0(funStandardInput),
 	NewSymbol("standard-output"):                Function0(funStandardOutput),
@@ -479,18 +542,41 @@ var autoLoadFunc = Functions{
 	NewSymbol("with-standard-output"):           SpecialF(cmdWithStandardOutput),
 	NewSymbol("write-byte"):                     Function2(funWriteByte),
 	NewSymbol("zerop"):                          Function1(funZerop),
-	symReportCondition:                          reportCondition,
 	// *sort*end*
 }
 
+// extensions are the functions registered with Export and ExportRange.
+// They are given to the Worlds created with New.
+var (
+	extensions   = Functions{}
+	extensionsMu sync.RWMutex
+)
+
+// Export registers the function which the Worlds created with New after
+// that have. It is usually called from init of the extension packages.
 func Export(name Symbol, value Callable) {
-	autoLoadFunc[name] = value
+	extensionsMu.Lock()
+	extensions[name] = value
+	extensionsMu.Unlock()
 }
 
 func ExportRange(v Functions) {
+	extensionsMu.Lock()
 	for key, val := range v {
-		autoLoadFunc[key] = val
+		extensions[key] = val
 	}
+	extensionsMu.Unlock()
+}
+
+// Export defines the function only in w.
+func (w *World) Export(name Symbol, value Callable) {
+	w.defineFunc(name, value)
+}
+
+// defineFunc binds name to value in the global function scope.
+func (w *World) defineFunc(name Symbol, value Callable) {
+	w.defun.Set(name, value)
+	w.funcVersion++
 }
 
 //go:embed embed/*
@@ -510,7 +596,7 @@ func (rw _RootWorld) Get(symbol Symbol) (Callable, bool) {
 	script, err := embedLisp.ReadFile(fname)
 	if err == nil {
 		value := &LispString{S: string(script)}
-		autoLoadFunc[symbol] = value
+		rw[symbol] = value
 		return value, true
 	}
 	return nil, false
@@ -521,17 +607,49 @@ func (rw _RootWorld) Set(symbol Symbol, value Callable) {
 }
 
 func (rw _RootWorld) Range(f func(Symbol, Callable) bool) {
+	for key, val := range rw {
+		if !f(key, val) {
+			return
+		}
+	}
 }
 
+// New returns a World which has the builtin functions and the extensions
+// registered with Export and ExportRange.
 func New() *World {
-	rwvars := &autoLoadVars
-	rwfuncs := _RootWorld{}
+	extensionsMu.RLock()
+	defer extensionsMu.RUnlock()
+	return NewWithExtensions(extensions)
+}
+
+// NewWithExtensions returns a World which has the builtin functions and
+// only the given extensions. Each World has its own global variables,
+// functions and trace table, so that the definitions in one World do not
+// appear in the others.
+func NewWithExtensions(ext ...Functions) *World {
+	rwvars := Variables{}
+	for key, val := range autoLoadVars {
+		rwvars[key] = val
+	}
+	constants := Constants{}
+	for key, val := range autoLoadConstants {
+		constants[key] = val
+	}
+	rwfuncs := _RootWorld{
+		symPrintObject:     newPrintObject(),
+		symReportCondition: newReportCondition(),
+	}
+	for _, e := range ext {
+		for key, val := range e {
+			rwfuncs[key] = val
+		}
+	}
 	w := &World{
 		shared: &shared{
 			global:    rwvars,
 			defun:     rwfuncs,
 			dynamic:   Variables{},
-			constants: autoLoadConstants,
+			constants: constants,
 			stdin:     &inputStream{_Reader: bufio.NewReader(os.Stdin), file: os.Stdin},
 			stdout:    newOutputFileStream(os.Stdout),
 			errout:    newOutputFileStream(os.Stderr),
@@ -629,6 +747,16 @@ func (w *World) InterpretBytes(ctx context.Context, code []byte) (Node, error) {
 	return w.InterpretNodes(ctx, compiled)
 }
 
+// InterpretFile is same as InterpretBytes, but the positions of the forms
+// are recorded as those in the file fname whose content is code.
+func (w *World) InterpretFile(ctx context.Context, fname string, code []byte) (Node, error) {
+	compiled, err := readAll(bytes.NewReader(code), fname)
+	if err != nil {
+		return nil, err
+	}
+	return w.InterpretNodes(ctx, compiled)
+}
+
 func (w *World) Let(scope Scope) *World {
 	return &World{
 		parent: w,
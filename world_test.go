@@ -3,7 +3,9 @@ package gmnlisp
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math"
+	"strings"
 	"testing"
 	"time"
 )
@@ -37,11 +39,10 @@ func TestWorld(t *testing.T) {
 		return
 	}
 
+	// the global variables of w1 must not be seen from w2
 	w2 := New()
-	if v, err := w2.Interpret(context.TODO(), `a`); err != nil {
-		t.Fatal(err.Error())
-	} else if v != String("A") {
-		t.Fatalf(`expect "A" but %#v`, v)
+	if v, err := w2.Interpret(context.TODO(), `a`); err == nil {
+		t.Fatalf(`expect undefined variable but %#v`, v)
 	}
 
 	w3 := New()
@@ -55,6 +56,37 @@ func TestWorld(t *testing.T) {
 	}
 }
 
+func TestNewWithExtensions(t *testing.T) {
+	ext := Functions{
+		NewSymbol("twice"): Function1(func(ctx context.Context, w *World, arg Node) (Node, error) {
+			return arg.(Integer) * 2, nil
+		}),
+	}
+	w1 := NewWithExtensions(ext)
+	if e := w1.Assert(`(twice 3)`, Integer(6)); e != "" {
+		t.Fatal(e)
+	}
+	w2 := NewWithExtensions()
+	if _, err := w2.Interpret(context.TODO(), `(twice 3)`); err == nil {
+		t.Fatal("the extension of w1 is found in w2")
+	}
+	if _, err := w1.Interpret(context.TODO(), `(defun foo () 1)`); err != nil {
+		t.Fatal(err.Error())
+	}
+	if _, err := w2.Interpret(context.TODO(), `(foo)`); err == nil {
+		t.Fatal("the function defined in w1 is found in w2")
+	}
+	w2.Export(NewSymbol("foo"), Function0(func(context.Context, *World) (Node, error) {
+		return Integer(2), nil
+	}))
+	if e := w2.Assert(`(foo)`, Integer(2)); e != "" {
+		t.Fatal(e)
+	}
+	if e := w1.Assert(`(foo)`, Integer(1)); e != "" {
+		t.Fatal(e)
+	}
+}
+
 func TestTokenizer(t *testing.T) {
 	assertEqual(t, `
 		(list 1 2 ;
@@ -184,3 +216,93 @@ func TestInt(t *testing.T) {
 	testInt(t, "#b11111111", 0xFF)
 	testInt(t, "#B1111111111111111", 0xFFFF)
 }
+
+func TestPositionError(t *testing.T) {
+	w := New()
+	_, err := w.InterpretFile(context.TODO(), "foo.lsp", []byte(`
+(defun foo (x)
+  (car x))
+(foo 1)`))
+	var posErr *PositionError
+	if !errors.As(err, &posErr) {
+		t.Fatalf("PositionError was not returned: %v", err)
+	}
+	if p := posErr.Pos.String(); p != "foo.lsp:3:3" {
+		t.Fatalf("expected foo.lsp:3:3, but %s", p)
+	}
+	if n := strings.Count(err.Error(), "foo.lsp:3:3"); n != 1 {
+		t.Fatalf("expected the position once, but %d times: %v", n, err)
+	}
+	var domainError *DomainError
+	if !errors.As(err, &domainError) {
+		t.Fatalf("DomainError was not returned: %v", err)
+	}
+}
+
+func TestConcurrentWorlds(t *testing.T) {
+	const n = 8
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			w := New()
+			code := fmt.Sprintf(`
+				(defglobal g %d)
+				(defclass <c> () ((x :initarg x :reader x-of)))
+				(defgeneric area (s))
+				(defmethod area ((s <c>)) (x-of s))
+				(defun f (n) (if (= n 0) 0 (+ 1 (f (- n 1)))))
+				(trace)
+				(let ((s (gensym)) (k (convert (format nil "sym-~A" g) <symbol>)))
+				  (+ (area (create (class <c>) 'x g)) (f 10) (length (list s k))
+				     (car (cadr (list 1 '(2))))))`, i)
+			v, err := w.Interpret(context.TODO(), code)
+			if err == nil && !v.Equals(Integer(i+14), STRICT) {
+				err = fmt.Errorf("%d: got %v", i, v)
+			}
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+}
+
+func TestFloatAllocs(t *testing.T) {
+	ctx := context.TODO()
+	w := New()
+	var x, y Node = Float(1.5), Float(2)
+	allocs := testing.AllocsPerRun(100, func() {
+		x.(Float).Add(ctx, w, y)
+		x.(Float).Multi(ctx, w, y)
+	})
+	// only the results are allocated
+	if allocs > 2 {
+		t.Fatalf("%v allocations", allocs)
+	}
+}
+
+func TestGensymInterned(t *testing.T) {
+	symbolManager.mu.RLock()
+	next := symbolManager.newCount
+	symbolManager.mu.RUnlock()
+	// the names which the next gensyms would make are interned already
+	taken := NewSymbol(fmt.Sprintf("-gensym-%d-", next))
+	NewSymbol(fmt.Sprintf("-gensym-%d-", next+1))
+	NewSymbol(fmt.Sprintf("gensym-interned-%d", next))
+
+	done := make(chan Node, 1)
+	go func() {
+		value, _ := funGensym(context.TODO(), New())
+		done <- value
+	}()
+	select {
+	case value := <-done:
+		if value == taken {
+			t.Fatalf("gensym returned the interned symbol %v", value)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("gensym did not return")
+	}
+}